@@ -2,7 +2,6 @@ package main
 
 import (
 	"bufio"
-	"crypto/sha256"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -14,8 +13,9 @@ import (
 	"strings"
 	"time"
 
+	"exporter/pkg/results"
 	"exporter/pkg/types"
-	"exporter/pkg/utils"
+	"keyvalue"
 )
 
 const (
@@ -32,6 +32,8 @@ func gatherRuntimeInfo(w http.ResponseWriter, r *http.Request) {
 
 	hashParam := r.URL.Query().Get("hash")
 	hash := hashParam == "" || hashParam == "true"
+	// the diagnostics of the fingerprints are only reported on demand
+	diagnostics := r.URL.Query().Get("diagnostics") == "true"
 
 	startTime := time.Now()
 	dataPath, err := triggerRuntimeInfoExtraction()
@@ -46,7 +48,7 @@ func gatherRuntimeInfo(w http.ResponseWriter, r *http.Request) {
 	}
 	defer os.RemoveAll(dataPath)
 
-	payload, err := collectWorkloadPayload(hash, dataPath)
+	payload, err := collectWorkloadPayload(hash, diagnostics, dataPath)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -81,11 +83,9 @@ func triggerRuntimeInfoExtraction() (string, error) {
 	return strings.TrimSpace(dataPath), nil
 }
 
-func collectWorkloadPayload(hash bool, dataPath string) (types.NodeRuntimeInfo, error) {
+func collectWorkloadPayload(hash bool, diagnostics bool, dataPath string) (types.NodeRuntimeInfo, error) {
 	payload := make(types.NodeRuntimeInfo)
 
-	h := sha256.New()
-
 	// Read all directory entries (1 per running container)
 	entries, err := os.ReadDir(dataPath)
 	if err != nil {
@@ -99,7 +99,7 @@ func collectWorkloadPayload(hash bool, dataPath string) (types.NodeRuntimeInfo,
 		containerDir := filepath.Join(dataPath, entry.Name())
 
 		// read the file container-info.txt to get the pod-name, pod-namespace, container-id fields
-		info, exists := utils.ReadPropertiesFile(filepath.Join(containerDir, "container-info.txt"))
+		info, exists := keyvalue.ReadFile(filepath.Join(containerDir, "container-info.txt"))
 		if !exists {
 			continue
 		}
@@ -107,39 +107,18 @@ func collectWorkloadPayload(hash bool, dataPath string) (types.NodeRuntimeInfo,
 		podName := info["pod-name"]
 		containerID := info["container-id"]
 
-		runtimeInfo := types.ContainerRuntimeInfo{}
-		// read the file os.txt to get the Operating System fingerprint
-		osFingerprintPath := filepath.Join(containerDir, "os.txt")
-		if info, exists := utils.ReadPropertiesFile(osFingerprintPath); exists {
-			runtimeInfo.Os = utils.HashString(hash, h, info["os-release-id"])
-			runtimeInfo.OsVersion = utils.HashString(hash, h, info["os-release-version-id"])
-		}
-		// read the file runtime-kind.txt to get the Runtime Kind fingerprint
-		runtimeKindPath := filepath.Join(containerDir, "runtime-kind.txt")
-		if info, exists := utils.ReadPropertiesFile(runtimeKindPath); exists {
-			runtimeInfo.Kind = utils.HashString(hash, h, info["runtime-kind"])
-			runtimeInfo.KindVersion = utils.HashString(hash, h, info["runtime-kind-version"])
-			runtimeInfo.KindImplementer = utils.HashString(hash, h, info["runtime-kind-implementer"])
-		}
-
-		// Read all other fingerprints files to fill the runtimes map
-		entries, err := os.ReadDir(containerDir)
+		// read the fingerprint results (merged by kind)
+		fingerprints, err := results.Read(containerDir)
 		if err != nil {
+			log.Printf("Unable to read the fingerprint results of %s: %s\n", containerDir, err)
 			continue
 		}
-		for _, file := range entries {
-			if !file.IsDir() && strings.HasSuffix(file.Name(), "-fingerprints.txt") {
-				log.Println("Got fingerprints file ", filepath.Join(containerDir, file.Name()))
-				if info, exists := utils.ReadPropertiesFile(filepath.Join(containerDir, file.Name())); exists {
-					for k, v := range info {
-						log.Println("Got key=value", k, v)
-
-						runtimeInfo.Runtimes = append(runtimeInfo.Runtimes, types.RuntimeComponent{
-							Name:    utils.HashString(hash, h, k),
-							Version: utils.HashString(hash, h, v),
-						})
-					}
-				}
+
+		runtimeInfo := results.RuntimeInfo(hash, fingerprints)
+		if diagnostics {
+			runtimeInfo.Diagnostics, err = results.Diagnostics(hash, containerDir)
+			if err != nil {
+				log.Printf("Unable to read the fingerprint diagnostics of %s: %s\n", containerDir, err)
 			}
 		}
 
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"exporter/pkg/image"
+	"exporter/pkg/results"
+	"exporter/pkg/types"
+)
+
+// The image scanner fingerprints a container image without running it.
+//
+// It unpacks the root filesystem of the image, derives the process that a container of the image would run
+// from the image configuration and runs the fingerprints (with the `fpr` binary) against that root filesystem.
+// It prints the runtime information of the container as JSON (the same data that the exporter reports for a running container).
+func main() {
+	fpr := flag.String("fpr", "fpr", "Path of the fpr binary that runs the fingerprints")
+	configPath := flag.String("config", "config.toml", "Path of the configuration of the fingerprints")
+	pluginDir := flag.String("plugins", "", "Directory of the fingerprint plugins (the plugins directory alongside the fpr binary if it is not set)")
+	hash := flag.Bool("hash", true, "Hash the values of the runtime information")
+	diagnostics := flag.Bool("diagnostics", false, "Report the diagnostics of the fingerprints")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: image-scanner [options] <image>\n\n"+
+			"<image> is an OCI image layout directory or a `docker save`/`podman save` archive.\n\noptions:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	runtimeInfo, err := scanImage(flag.Arg(0), *fpr, *configPath, *pluginDir, *hash, *diagnostics)
+	if err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(runtimeInfo); err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+}
+
+func scanImage(imagePath string, fpr string, configPath string, pluginDir string, hash bool, diagnostics bool) (types.ContainerRuntimeInfo, error) {
+	workDir, err := os.MkdirTemp("", "image-scanner-")
+	if err != nil {
+		return types.ContainerRuntimeInfo{}, err
+	}
+	defer os.RemoveAll(workDir)
+	rootDir := filepath.Join(workDir, "rootfs")
+	outputDir := filepath.Join(workDir, "out")
+
+	log.Printf("📦 Unpacking image %s\n", imagePath)
+	imageConfig, err := image.Unpack(imagePath, rootDir)
+	if err != nil {
+		return types.ContainerRuntimeInfo{}, err
+	}
+	process, err := imageConfig.Process(rootDir)
+	if err != nil {
+		return types.ContainerRuntimeInfo{}, err
+	}
+	log.Printf("🔎 Fingerprinting process %q (cwd: %s)\n", process.CommandLine, process.Cwd)
+
+	// the fingerprints read their configuration and the process context from the output directory
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return types.ContainerRuntimeInfo{}, err
+	}
+	if err := copyConfig(configPath, outputDir); err != nil {
+		return types.ContainerRuntimeInfo{}, fmt.Errorf("unable to read configuration: %w", err)
+	}
+	if err := process.WriteProcessContext(outputDir); err != nil {
+		return types.ContainerRuntimeInfo{}, err
+	}
+
+	cmd := exec.Command(fpr, "process", "--root", rootDir, outputDir)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if pluginDir != "" {
+		cmd.Env = append(os.Environ(), "FPR_PLUGIN_DIR="+pluginDir)
+	}
+	if err := cmd.Run(); err != nil {
+		return types.ContainerRuntimeInfo{}, fmt.Errorf("unable to run the fingerprints: %w", err)
+	}
+
+	fingerprints, err := results.Read(outputDir)
+	if err != nil {
+		return types.ContainerRuntimeInfo{}, err
+	}
+	runtimeInfo := results.RuntimeInfo(hash, fingerprints)
+	if diagnostics {
+		runtimeInfo.Diagnostics, err = results.Diagnostics(hash, outputDir)
+		if err != nil {
+			return types.ContainerRuntimeInfo{}, err
+		}
+	}
+	return runtimeInfo, nil
+}
+
+// copyConfig copies the configuration file and the drop-in files (*.toml) of the config.d directory alongside it
+// to the output directory (the fingerprints merge the drop-in files into the configuration)
+func copyConfig(configPath string, outputDir string) error {
+	dropIns, err := filepath.Glob(filepath.Join(filepath.Dir(configPath), "config.d", "*.toml"))
+	if err != nil {
+		return err
+	}
+	files := map[string]string{configPath: filepath.Join(outputDir, "config.toml")}
+	for _, dropIn := range dropIns {
+		files[dropIn] = filepath.Join(outputDir, "config.d", filepath.Base(dropIn))
+	}
+	for source, target := range files {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
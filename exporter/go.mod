@@ -9,6 +9,8 @@ require (
 	github.com/stretchr/testify v1.9.0
 	k8s.io/api v0.30.3
 	k8s.io/apimachinery v0.30.3
+	keyvalue v0.0.0
+	rootfs v0.0.0
 	sigs.k8s.io/e2e-framework v0.4.0
 )
 
@@ -70,3 +72,7 @@ require (
 	sigs.k8s.io/structured-merge-diff/v4 v4.4.1 // indirect
 	sigs.k8s.io/yaml v1.4.0 // indirect
 )
+
+replace keyvalue => ../keyvalue
+
+replace rootfs => ../rootfs
@@ -0,0 +1,299 @@
+// Package image unpacks the root filesystem of a container image
+// (an OCI image layout or a `docker save`/`podman save` archive) and describes the process that it would run.
+package image
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+)
+
+// Config is the execution configuration of the image
+// (the `config` object of the OCI image configuration, which is also used by the docker image configuration)
+type Config struct {
+	Entrypoint []string `json:"Entrypoint"`
+	Cmd        []string `json:"Cmd"`
+	Env        []string `json:"Env"`
+	WorkingDir string   `json:"WorkingDir"`
+	// user (or user:group) running the process ("1001", "1001:0" or a user name)
+	User string `json:"User"`
+}
+
+// descriptor of an OCI content (https://github.com/opencontainers/image-spec/blob/main/descriptor.md)
+type descriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Platform  *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform,omitempty"`
+}
+
+// OCI image index (index.json)
+type index struct {
+	Manifests []descriptor `json:"manifests"`
+}
+
+// OCI image manifest
+type manifest struct {
+	Config descriptor   `json:"config"`
+	Layers []descriptor `json:"layers"`
+}
+
+// entry of the manifest.json file of a `docker save` archive
+type dockerManifest struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+// Unpack flattens the layers of the image into rootDir and returns the configuration of the image.
+//
+// The image is either an OCI image layout directory or a tar archive (optionally gzipped) of an OCI image layout
+// or of a `docker save`/`podman save` image.
+func Unpack(imagePath string, rootDir string) (Config, error) {
+	info, err := os.Stat(imagePath)
+	if err != nil {
+		return Config{}, err
+	}
+	imageDir := imagePath
+	if !info.IsDir() {
+		imageDir, err = os.MkdirTemp("", "image-")
+		if err != nil {
+			return Config{}, err
+		}
+		defer os.RemoveAll(imageDir)
+		if err := extractArchive(imagePath, imageDir); err != nil {
+			return Config{}, fmt.Errorf("unable to extract image archive %s: %w", imagePath, err)
+		}
+	}
+
+	configPath, layerPaths, err := readManifest(imageDir)
+	if err != nil {
+		return Config{}, err
+	}
+	config, err := readConfig(configPath)
+	if err != nil {
+		return Config{}, fmt.Errorf("unable to read image configuration %s: %w", configPath, err)
+	}
+
+	if err := os.MkdirAll(rootDir, 0755); err != nil {
+		return Config{}, err
+	}
+	for _, layerPath := range layerPaths {
+		if err := applyLayer(rootDir, layerPath); err != nil {
+			return Config{}, fmt.Errorf("unable to apply layer %s: %w", filepath.Base(layerPath), err)
+		}
+	}
+	return config, nil
+}
+
+// readManifest returns the paths of the image configuration and of the layers (from the lowest to the highest layer)
+func readManifest(imageDir string) (string, []string, error) {
+	indexPath := filepath.Join(imageDir, "index.json")
+	if _, err := os.Stat(indexPath); err == nil {
+		return readOCIManifest(imageDir, indexPath)
+	}
+
+	dockerManifestPath := filepath.Join(imageDir, "manifest.json")
+	content, err := os.ReadFile(dockerManifestPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s is neither an OCI image layout nor a docker image archive", imageDir)
+	}
+	var manifests []dockerManifest
+	if err := json.Unmarshal(content, &manifests); err != nil {
+		return "", nil, fmt.Errorf("invalid manifest %s: %w", dockerManifestPath, err)
+	}
+	if len(manifests) == 0 {
+		return "", nil, fmt.Errorf("no image in manifest %s", dockerManifestPath)
+	}
+	configPath, err := archivePath(imageDir, manifests[0].Config)
+	if err != nil {
+		return "", nil, err
+	}
+	layerPaths := []string{}
+	for _, layer := range manifests[0].Layers {
+		layerPath, err := archivePath(imageDir, layer)
+		if err != nil {
+			return "", nil, err
+		}
+		layerPaths = append(layerPaths, layerPath)
+	}
+	return configPath, layerPaths, nil
+}
+
+// readOCIManifest returns the paths of the image configuration and layers of the OCI image layout.
+// If the image index lists several platforms, the manifest of the platform of the host (or the first one) is used.
+func readOCIManifest(imageDir string, indexPath string) (string, []string, error) {
+	content, err := os.ReadFile(indexPath)
+	if err != nil {
+		return "", nil, err
+	}
+	for {
+		var idx index
+		if err := json.Unmarshal(content, &idx); err != nil {
+			return "", nil, fmt.Errorf("invalid image index: %w", err)
+		}
+		if len(idx.Manifests) == 0 {
+			return "", nil, fmt.Errorf("no manifest in image index")
+		}
+		selected := idx.Manifests[0]
+		for _, desc := range idx.Manifests {
+			if desc.Platform != nil && desc.Platform.OS == "linux" && desc.Platform.Architecture == runtime.GOARCH {
+				selected = desc
+				break
+			}
+		}
+		content, err = readBlob(imageDir, selected.Digest)
+		if err != nil {
+			return "", nil, err
+		}
+		// nested image index (multi-platform image)
+		if selected.MediaType == mediaTypeOCIIndex || selected.MediaType == mediaTypeDockerManifestList {
+			continue
+		}
+		break
+	}
+
+	var m manifest
+	if err := json.Unmarshal(content, &m); err != nil {
+		return "", nil, fmt.Errorf("invalid image manifest: %w", err)
+	}
+	configPath, err := blobPath(imageDir, m.Config.Digest)
+	if err != nil {
+		return "", nil, err
+	}
+	layerPaths := []string{}
+	for _, layer := range m.Layers {
+		layerPath, err := blobPath(imageDir, layer.Digest)
+		if err != nil {
+			return "", nil, err
+		}
+		layerPaths = append(layerPaths, layerPath)
+	}
+	return configPath, layerPaths, nil
+}
+
+// blobPath returns the path of the blob with that digest (blobs/<algorithm>/<encoded>)
+func blobPath(imageDir string, digest string) (string, error) {
+	algorithm, encoded, found := strings.Cut(digest, ":")
+	if !found || !filepath.IsLocal(algorithm) || !filepath.IsLocal(encoded) {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return filepath.Join(imageDir, "blobs", algorithm, encoded), nil
+}
+
+func readBlob(imageDir string, digest string) ([]byte, error) {
+	path, err := blobPath(imageDir, digest)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
+
+// archivePath returns the path of a file referenced by the manifest of a docker archive
+func archivePath(imageDir string, name string) (string, error) {
+	if !filepath.IsLocal(name) {
+		return "", fmt.Errorf("invalid path %q in image manifest", name)
+	}
+	return filepath.Join(imageDir, name), nil
+}
+
+func readConfig(configPath string) (Config, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return Config{}, err
+	}
+	var imageConfig struct {
+		Config Config `json:"config"`
+	}
+	if err := json.Unmarshal(content, &imageConfig); err != nil {
+		return Config{}, err
+	}
+	return imageConfig.Config, nil
+}
+
+// openTar returns a tar reader of the archive (that can be gzipped)
+func openTar(file *os.File) (*tar.Reader, error) {
+	r := bufio.NewReader(file)
+	magic, err := r.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return tar.NewReader(gz), nil
+	case bytes.Equal(magic, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return nil, fmt.Errorf("zstd compressed layers are not supported")
+	}
+	return tar.NewReader(r), nil
+}
+
+// extractArchive extracts the directories and regular files of the image archive to dir
+func extractArchive(archive string, dir string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tr, err := openTar(file)
+	if err != nil {
+		return err
+	}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid path %q in image archive", header.Name)
+		}
+		path := filepath.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := writeFile(path, tr, 0644); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
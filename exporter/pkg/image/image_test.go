@@ -0,0 +1,236 @@
+package image
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tarEntry struct {
+	name     string
+	typeflag byte
+	content  string
+	linkname string
+}
+
+func createTar(t *testing.T, entries []tarEntry, compress bool) []byte {
+	var buffer bytes.Buffer
+	var tw *tar.Writer
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(&buffer)
+		tw = tar.NewWriter(gz)
+	} else {
+		tw = tar.NewWriter(&buffer)
+	}
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Typeflag: entry.typeflag, Linkname: entry.linkname, Mode: 0644}
+		switch entry.typeflag {
+		case tar.TypeDir:
+			header.Mode = 0755
+		case tar.TypeReg:
+			header.Size = int64(len(entry.content))
+		}
+		assert.NoError(t, tw.WriteHeader(header))
+		if entry.typeflag == tar.TypeReg {
+			_, err := tw.Write([]byte(entry.content))
+			assert.NoError(t, err)
+		}
+	}
+	assert.NoError(t, tw.Close())
+	if gz != nil {
+		assert.NoError(t, gz.Close())
+	}
+	return buffer.Bytes()
+}
+
+var imageConfig = `{"architecture": "amd64", "os": "linux", "config": {
+	"Entrypoint": ["/bin/sh", "-c"],
+	"Cmd": ["exec java -jar app.jar"],
+	"Env": ["PATH=/usr/local/bin:/usr/bin", "JAVA_HOME=/usr/lib/jvm/jre"],
+	"WorkingDir": "/app"
+}}`
+
+var layers = [][]tarEntry{
+	{
+		{name: "etc/", typeflag: tar.TypeDir},
+		{name: "etc/os-release", typeflag: tar.TypeReg, content: "ID=fedora\n"},
+		{name: "etc/removed", typeflag: tar.TypeReg, content: "removed"},
+		{name: "opt/cache/", typeflag: tar.TypeDir},
+		{name: "opt/cache/old", typeflag: tar.TypeReg, content: "old"},
+		{name: "usr/bin/", typeflag: tar.TypeDir},
+		{name: "usr/bin/java", typeflag: tar.TypeReg, content: "java"},
+		// symbolic links that point outside of the root filesystem
+		{name: "escape", typeflag: tar.TypeSymlink, linkname: "../../../../../../tmp"},
+		{name: "passwd", typeflag: tar.TypeSymlink, linkname: "/etc/passwd"},
+	},
+	{
+		{name: "etc/os-release", typeflag: tar.TypeReg, content: "ID=rhel\n"},
+		{name: "etc/.wh.removed", typeflag: tar.TypeReg},
+		{name: "opt/cache/new", typeflag: tar.TypeReg, content: "new"},
+		{name: "opt/cache/.wh..wh..opq", typeflag: tar.TypeReg},
+		{name: "app/", typeflag: tar.TypeDir},
+		{name: "app/app.jar", typeflag: tar.TypeReg, content: "jar"},
+		// written through the symbolic links inside the root filesystem
+		{name: "escape/escaped", typeflag: tar.TypeReg, content: "escaped"},
+		{name: "passwd", typeflag: tar.TypeReg, content: "root:x:0:0"},
+	},
+}
+
+func writeBlob(t *testing.T, dir string, content []byte) string {
+	sum := sha256.Sum256(content)
+	encoded := hex.EncodeToString(sum[:])
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "blobs", "sha256", encoded), content, 0644))
+	return "sha256:" + encoded
+}
+
+func createOCILayout(t *testing.T) string {
+	dir := t.TempDir()
+	m := manifest{Config: descriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: writeBlob(t, dir, []byte(imageConfig))}}
+	for _, layer := range layers {
+		m.Layers = append(m.Layers, descriptor{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip", Digest: writeBlob(t, dir, createTar(t, layer, true))})
+	}
+	manifestContent, _ := json.Marshal(m)
+	idx, _ := json.Marshal(map[string]any{
+		"schemaVersion": 2,
+		"manifests":     []descriptor{{MediaType: "application/vnd.oci.image.manifest.v1+json", Digest: writeBlob(t, dir, manifestContent)}},
+	})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "index.json"), idx, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "oci-layout"), []byte(`{"imageLayoutVersion": "1.0.0"}`), 0644))
+	return dir
+}
+
+func createDockerArchive(t *testing.T) string {
+	entries := []tarEntry{{name: "config.json", typeflag: tar.TypeReg, content: imageConfig}}
+	dockerManifest := dockerManifest{Config: "config.json"}
+	for i, layer := range layers {
+		name := filepath.Join(string(rune('a'+i)), "layer.tar")
+		entries = append(entries, tarEntry{name: name, typeflag: tar.TypeReg, content: string(createTar(t, layer, false))})
+		dockerManifest.Layers = append(dockerManifest.Layers, name)
+	}
+	manifestContent, _ := json.Marshal([]any{dockerManifest})
+	entries = append(entries, tarEntry{name: "manifest.json", typeflag: tar.TypeReg, content: string(manifestContent)})
+
+	archive := filepath.Join(t.TempDir(), "image.tar")
+	assert.NoError(t, os.WriteFile(archive, createTar(t, entries, false), 0644))
+	return archive
+}
+
+func assertRootFilesystem(t *testing.T, rootDir string) {
+	content, err := os.ReadFile(filepath.Join(rootDir, "etc", "os-release"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID=rhel\n", string(content))
+	assert.NoFileExists(t, filepath.Join(rootDir, "etc", "removed"))
+	assert.NoFileExists(t, filepath.Join(rootDir, "opt", "cache", "old"))
+	assert.FileExists(t, filepath.Join(rootDir, "opt", "cache", "new"))
+	assert.FileExists(t, filepath.Join(rootDir, "app", "app.jar"))
+	// the files written through the symbolic links stay in the root filesystem
+	assert.FileExists(t, filepath.Join(rootDir, "tmp", "escaped"))
+	content, err = os.ReadFile(filepath.Join(rootDir, "passwd"))
+	assert.NoError(t, err)
+	assert.Equal(t, "root:x:0:0", string(content))
+}
+
+func TestUnpackOCILayout(t *testing.T) {
+	rootDir := filepath.Join(t.TempDir(), "rootfs")
+	config, err := Unpack(createOCILayout(t), rootDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/bin/sh", "-c"}, config.Entrypoint)
+	assertRootFilesystem(t, rootDir)
+}
+
+func TestUnpackDockerArchive(t *testing.T) {
+	rootDir := filepath.Join(t.TempDir(), "rootfs")
+	config, err := Unpack(createDockerArchive(t), rootDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "/app", config.WorkingDir)
+	assertRootFilesystem(t, rootDir)
+}
+
+func TestApplyLayerSymlinkAfterMissingComponent(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "escape-target")
+	assert.NoError(t, os.MkdirAll(target, 0755))
+	layerPath := filepath.Join(t.TempDir(), "layer.tar")
+	assert.NoError(t, os.WriteFile(layerPath, createTar(t, []tarEntry{
+		{name: "link2", typeflag: tar.TypeSymlink, linkname: target},
+		// like the kernel, the `..` after a missing component does not lead to the absolute symbolic link
+		{name: "a", typeflag: tar.TypeSymlink, linkname: "/nonexist/../link2"},
+		{name: "a/.wh.kept", typeflag: tar.TypeReg},
+		{name: "a/pwned", typeflag: tar.TypeReg, content: "pwned"},
+	}, false), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(target, "kept"), []byte("kept"), 0644))
+
+	rootDir := t.TempDir()
+	err := applyLayer(rootDir, layerPath)
+	assert.True(t, os.IsNotExist(err), "unexpected error %v", err)
+	assert.NoFileExists(t, filepath.Join(target, "pwned"))
+	assert.FileExists(t, filepath.Join(target, "kept"))
+}
+
+func TestUnpackInvalidImage(t *testing.T) {
+	_, err := Unpack(t.TempDir(), t.TempDir())
+	assert.ErrorContains(t, err, "is neither an OCI image layout nor a docker image archive")
+}
+
+func TestProcess(t *testing.T) {
+	rootDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(rootDir, "usr", "bin"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(rootDir, "usr", "bin", "java"), nil, 0755))
+
+	var config Config
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"Entrypoint": ["/bin/sh", "-c"],
+		"Cmd": ["exec java -jar app.jar"],
+		"Env": ["PATH=/usr/local/bin:/usr/bin"],
+		"WorkingDir": "/app",
+		"User": "1001:0"
+	}`), &config))
+	process, err := config.Process(rootDir)
+	assert.NoError(t, err)
+	uid := 1001
+	assert.Equal(t, Process{
+		CommandLine: []string{"/usr/bin/java", "-jar", "app.jar"},
+		Environ:     []string{"PATH=/usr/local/bin:/usr/bin"},
+		Cwd:         "/app",
+		UID:         &uid,
+	}, process)
+
+	outputDir := t.TempDir()
+	assert.NoError(t, process.WriteProcessContext(outputDir))
+	content, err := os.ReadFile(filepath.Join(outputDir, "process.json"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"version": 1,
+		"uid": 1001,
+		"cwd": "/app",
+		"command-line": ["/usr/bin/java", "-jar", "app.jar"],
+		"environ": {"PATH": "/usr/local/bin:/usr/bin"}
+	}`, string(content))
+
+	// the shell is the process when the command is not a simple command
+	config = Config{Cmd: []string{"/bin/sh", "-c", "java -jar app.jar && sleep 10"}}
+	process, err = config.Process(rootDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/bin/sh", "-c", "java -jar app.jar && sleep 10"}, process.CommandLine)
+	assert.Equal(t, "/", process.Cwd)
+	// the process runs as root
+	assert.Equal(t, 0, *process.UID)
+
+	// the UID of a user name is unknown
+	config = Config{Cmd: []string{"nginx"}, User: "nginx"}
+	process, err = config.Process(rootDir)
+	assert.NoError(t, err)
+	assert.Nil(t, process.UID)
+
+	_, err = Config{}.Process(rootDir)
+	assert.EqualError(t, err, "the image has no Entrypoint or Cmd")
+}
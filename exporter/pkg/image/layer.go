@@ -0,0 +1,182 @@
+package image
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rootfs"
+)
+
+const (
+	// prefix of the whiteout files that delete a file of the lower layers
+	whiteoutPrefix = ".wh."
+	// whiteout file that hides the content of its directory in the lower layers
+	opaqueWhiteout = ".wh..wh..opq"
+)
+
+// applyLayer applies the changes of the layer to the root filesystem
+// (https://github.com/opencontainers/image-spec/blob/main/layer.md).
+//
+// The paths are resolved inside rootDir so that the entries of a layer never escape it.
+// Ownership, devices and named pipes are not restored as they are not needed by the fingerprints.
+func applyLayer(rootDir string, layerPath string) error {
+	file, err := os.Open(layerPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tr, err := openTar(file)
+	if err != nil {
+		return err
+	}
+	// the paths added by this layer (that are not hidden by an opaque whiteout of this layer)
+	added := make(map[string]bool)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.Clean("/" + header.Name)
+		if name == "/" {
+			continue
+		}
+		dir, base := filepath.Split(name)
+
+		switch {
+		case base == opaqueWhiteout:
+			if err := removeLowerChildren(rootDir, filepath.Clean(dir), added); err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(base, whiteoutPrefix):
+			path, err := resolveParent(rootDir, filepath.Join(dir, strings.TrimPrefix(base, whiteoutPrefix)))
+			if err != nil {
+				return err
+			}
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		path, err := resolveParent(rootDir, name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if info, err := os.Lstat(path); err == nil && !info.IsDir() {
+				if err := os.Remove(path); err != nil {
+					return err
+				}
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			// the directory must stay writable to apply the next layers
+			if err := os.Chmod(path, header.FileInfo().Mode().Perm()|0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := removeNonDir(path); err != nil {
+				return err
+			}
+			if err := writeFile(path, tr, header.FileInfo().Mode().Perm()|0600); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := removeNonDir(path); err != nil {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, path); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			target, err := resolveParent(rootDir, filepath.Clean("/"+header.Linkname))
+			if err != nil {
+				return err
+			}
+			if err := removeNonDir(path); err != nil {
+				return err
+			}
+			if err := os.Link(target, path); err != nil {
+				return fmt.Errorf("unable to link %s to %s: %w", name, header.Linkname, err)
+			}
+		default:
+			// devices and named pipes
+			continue
+		}
+		added[name] = true
+	}
+}
+
+// removeLowerChildren removes the content of the directory that comes from the lower layers
+func removeLowerChildren(rootDir string, dir string, added map[string]bool) error {
+	path, err := resolvePath(rootDir, dir)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if added[filepath.Join(dir, entry.Name())] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(path, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeNonDir removes the file at path (if any) so that it can be replaced
+func removeNonDir(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return os.RemoveAll(path)
+	}
+	return os.Remove(path)
+}
+
+// resolveParent returns the path in rootDir of the given path of the root filesystem.
+// The symbolic links of its parent directories are resolved inside rootDir; the last element is not resolved.
+func resolveParent(rootDir string, path string) (string, error) {
+	dir, base := filepath.Split(path)
+	resolvedDir, err := resolvePath(rootDir, dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolvedDir, base), nil
+}
+
+// resolvePath returns the path in rootDir of the given path of the root filesystem.
+// Its symbolic links are resolved as if rootDir was the root directory (see the rootfs module).
+func resolvePath(rootDir string, path string) (string, error) {
+	resolved, err := rootfs.Resolve(rootDir, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(rootDir, resolved), nil
+}
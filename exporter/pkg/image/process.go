@@ -0,0 +1,138 @@
+package image
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// shells whose `-c` argument is the command line of the process
+var shells = []string{"sh", "bash", "ash", "dash"}
+
+// Process is the process that a container of the image would run
+type Process struct {
+	// the executable and its arguments (the Entrypoint followed by the Cmd of the image)
+	CommandLine []string
+	// the environment variables (KEY=VALUE)
+	Environ []string
+	// the current working directory
+	Cwd string
+	// the UID of the user running the process (nil if the image user is a user name)
+	UID *int
+}
+
+// Process returns the process that a container of the image would run from the root filesystem in rootDir.
+//
+// The executable is searched in the directories of the PATH environment variable of the image
+// (as the container runtime does when the container starts).
+// When the command line is in shell form (`/bin/sh -c "exec java -jar app.jar"`) and the command is a simple command
+// (without shell operators, quotes or variables), the process is the command run by the shell.
+func (config Config) Process(rootDir string) (Process, error) {
+	commandLine := append(append([]string{}, config.Entrypoint...), config.Cmd...)
+	if len(commandLine) == 0 {
+		return Process{}, fmt.Errorf("the image has no Entrypoint or Cmd")
+	}
+	if command, found := shellCommand(commandLine); found {
+		commandLine = command
+	}
+
+	process := Process{
+		CommandLine: commandLine,
+		Environ:     config.Env,
+		Cwd:         config.WorkingDir,
+	}
+	if process.Cwd == "" {
+		process.Cwd = "/"
+	}
+	// the container runs as root if the image has no user
+	user, _, _ := strings.Cut(config.User, ":")
+	if user == "" {
+		user = "0"
+	}
+	if uid, err := strconv.Atoi(user); err == nil {
+		process.UID = &uid
+	}
+	if !strings.Contains(commandLine[0], "/") {
+		if executable, found := lookPath(rootDir, commandLine[0], process.env("PATH")); found {
+			process.CommandLine[0] = executable
+		}
+	}
+	return process, nil
+}
+
+func (process Process) env(name string) string {
+	for _, env := range process.Environ {
+		if value, found := strings.CutPrefix(env, name+"="); found {
+			return value
+		}
+	}
+	return ""
+}
+
+// WriteProcessContext writes the process context of the process to the process.json file of the output directory
+// of the fingerprints (see the ProcessContext of the fingerprints module)
+func (process Process) WriteProcessContext(outputDir string) error {
+	environ := make(map[string]string)
+	for _, env := range process.Environ {
+		if key, value, found := strings.Cut(env, "="); found {
+			environ[key] = value
+		}
+	}
+	content, err := json.MarshalIndent(struct {
+		Version     int               `json:"version"`
+		UID         *int              `json:"uid,omitempty"`
+		Cwd         string            `json:"cwd"`
+		CommandLine []string          `json:"command-line"`
+		Environ     map[string]string `json:"environ"`
+	}{
+		Version:     1,
+		UID:         process.UID,
+		Cwd:         process.Cwd,
+		CommandLine: process.CommandLine,
+		Environ:     environ,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, "process.json"), content, 0644)
+}
+
+// shellCommand returns the simple command run by a shell-form command line
+func shellCommand(commandLine []string) ([]string, bool) {
+	if len(commandLine) != 3 || commandLine[1] != "-c" {
+		return nil, false
+	}
+	isShell := false
+	for _, shell := range shells {
+		isShell = isShell || filepath.Base(commandLine[0]) == shell
+	}
+	if !isShell || strings.ContainsAny(commandLine[2], ";&|<>()$`'\"\\") {
+		return nil, false
+	}
+	command := strings.Fields(commandLine[2])
+	if len(command) > 0 && command[0] == "exec" {
+		command = command[1:]
+	}
+	return command, len(command) > 0
+}
+
+// lookPath returns the path of the executable in the directories of the PATH of the root filesystem
+func lookPath(rootDir string, executable string, pathEnvVar string) (string, bool) {
+	for _, dir := range strings.Split(pathEnvVar, string(os.PathListSeparator)) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+		path := filepath.Join(dir, executable)
+		resolved, err := resolvePath(rootDir, path)
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(resolved); err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0 {
+			return path, true
+		}
+	}
+	return "", false
+}
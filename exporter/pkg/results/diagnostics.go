@@ -0,0 +1,50 @@
+package results
+
+import (
+	"crypto/sha256"
+	"sort"
+	"strconv"
+	"strings"
+
+	"exporter/pkg/types"
+	"exporter/pkg/utils"
+)
+
+// kind of the results that describe how a fingerprint ran
+const kindDiagnostic = "diagnostic"
+
+// Diagnostics returns the diagnostics of the fingerprints that ran for the container, ordered by fingerprint.
+// If hash is true, the error messages (that can contain paths of the container) are hashed.
+func Diagnostics(hash bool, containerDir string) ([]types.FingerprintDiagnostic, error) {
+	results, err := readResults(containerDir)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	diagnostics := []types.FingerprintDiagnostic{}
+	for _, result := range results {
+		if result.Kind != kindDiagnostic {
+			continue
+		}
+		values := result.Values
+		diagnostic := types.FingerprintDiagnostic{
+			Fingerprint: result.Fingerprint,
+			Status:      values["status"],
+			Error:       utils.HashString(hash, h, values["error"]),
+		}
+		// the usage is absent from the diagnostics of the crashed fingerprints
+		diagnostic.DurationMs, _ = strconv.ParseInt(values["duration-ms"], 10, 64)
+		diagnostic.Results, _ = strconv.Atoi(values["results"])
+		diagnostic.BytesRead, _ = strconv.ParseInt(values["bytes-read"], 10, 64)
+		diagnostic.ZipEntries, _ = strconv.Atoi(values["zip-entries"])
+		diagnostic.NestedJarDepth, _ = strconv.Atoi(values["nested-jar-depth"])
+		if values["exceeded-budgets"] != "" {
+			diagnostic.ExceededBudgets = strings.Split(values["exceeded-budgets"], ",")
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Fingerprint < diagnostics[j].Fingerprint
+	})
+	return diagnostics, nil
+}
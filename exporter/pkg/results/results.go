@@ -0,0 +1,167 @@
+// Package results reads the JSON results written by the fingerprints and merges them per kind.
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Version of the result format supported by the exporter
+const supportedVersion = 1
+
+// Evidence is a source that a fingerprint result is based on
+type Evidence struct {
+	Type   string `json:"type"`
+	Path   string `json:"path,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Result is the JSON document written by a fingerprint (see the fingerprint package of the fingerprints module)
+type Result struct {
+	Version     int               `json:"version"`
+	Fingerprint string            `json:"fingerprint"`
+	Kind        string            `json:"kind"`
+	Values      map[string]string `json:"values"`
+	Evidence    []Evidence        `json:"evidence,omitempty"`
+	Confidence  string            `json:"confidence"`
+}
+
+var confidenceRanks = map[string]int{
+	"high":   3,
+	"medium": 2,
+	"low":    1,
+}
+
+// When results of the same kind have the same confidence, the result of the fingerprint that comes first
+// in this list wins (the fingerprints that read the runtime metadata come before the ones that run the executable).
+// The results of fingerprints that are not listed come after.
+var fingerprintPrecedence = []string{
+	"java-version",
+	"native-executable",
+	"kind-executable",
+}
+
+// isMergedKind returns true if the values of all the results of that kind are merged
+// (for example the runtime components found by different fingerprints or for different processes).
+// For the other kinds, the values of the result with the highest precedence are kept.
+func isMergedKind(kind string) bool {
+	return strings.HasSuffix(kind, "-fingerprints") || kind == "os-packages" || kind == "posture"
+}
+
+// Read reads the fingerprint results of the container directory and returns the values of each kind.
+//
+// The results are ordered by precedence: the highest confidence first,
+// then the fingerprint precedence, then the name of the result file.
+// For merged kinds, a key takes the value of the result with the highest precedence that contains that key.
+// For other kinds, the values of the result with the highest precedence are returned.
+// The diagnostics of the fingerprints are not runtime information and are not returned (see Diagnostics).
+func Read(containerDir string) (map[string]map[string]string, error) {
+	results, err := readResults(containerDir)
+	if err != nil {
+		return nil, err
+	}
+
+	resultsByKind := make(map[string][]Result)
+	for _, result := range results {
+		if result.Kind == kindDiagnostic {
+			continue
+		}
+		resultsByKind[result.Kind] = append(resultsByKind[result.Kind], result)
+	}
+
+	values := make(map[string]map[string]string)
+	for kind, results := range resultsByKind {
+		sort.SliceStable(results, func(i, j int) bool {
+			return hasPrecedence(results[i], results[j])
+		})
+		if !isMergedKind(kind) {
+			values[kind] = results[0].Values
+			continue
+		}
+		merged := make(map[string]string)
+		for _, result := range results {
+			for k, v := range result.Values {
+				if _, exists := merged[k]; !exists {
+					merged[k] = v
+				}
+			}
+		}
+		values[kind] = merged
+	}
+	return values, nil
+}
+
+// readResults reads the valid fingerprint results of the container directory
+func readResults(containerDir string) ([]Result, error) {
+	// the results are named <kind>.<fingerprint>.<suffix>.json (the process.json process context is not a result)
+	files, err := filepath.Glob(filepath.Join(containerDir, "*.*.*.json"))
+	if err != nil {
+		return nil, err
+	}
+	results := []Result{}
+	for _, file := range files {
+		result, err := readResult(file)
+		if err != nil {
+			log.Printf("Ignoring fingerprint result %s: %s\n", file, err)
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func readResult(file string) (Result, error) {
+	var result Result
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return result, err
+	}
+	if result.Version != supportedVersion {
+		return result, fmt.Errorf("unsupported result version %d", result.Version)
+	}
+	if result.Kind == "" {
+		return result, fmt.Errorf("missing result kind")
+	}
+	return result, nil
+}
+
+// hasPrecedence returns true if the result a has precedence over the result b
+func hasPrecedence(a Result, b Result) bool {
+	if confidenceA, confidenceB := confidenceRanks[a.Confidence], confidenceRanks[b.Confidence]; confidenceA != confidenceB {
+		return confidenceA > confidenceB
+	}
+	if precedenceA, precedenceB := fingerprintRank(a.Fingerprint), fingerprintRank(b.Fingerprint); precedenceA != precedenceB {
+		return precedenceA < precedenceB
+	}
+	// the names of the result files are random: the ties are broken on the content of the results
+	if a.Fingerprint != b.Fingerprint {
+		return a.Fingerprint < b.Fingerprint
+	}
+	return slices.Compare(sortedValues(a), sortedValues(b)) < 0
+}
+
+// sortedValues returns the key=value pairs of the values of the result, sorted
+func sortedValues(result Result) []string {
+	values := make([]string, 0, len(result.Values))
+	for k, v := range result.Values {
+		values = append(values, k+"="+v)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func fingerprintRank(fingerprint string) int {
+	if rank := slices.Index(fingerprintPrecedence, fingerprint); rank >= 0 {
+		return rank
+	}
+	return len(fingerprintPrecedence)
+}
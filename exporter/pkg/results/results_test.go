@@ -0,0 +1,221 @@
+package results
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"exporter/pkg/types"
+)
+
+func writeResult(t *testing.T, dir string, file string, result Result) {
+	content, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, file), content, 0644))
+}
+
+func TestReadSelectsResultWithHighestPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, "runtime-kind.kind-executable.1.json", Result{
+		Version:     1,
+		Fingerprint: "kind-executable",
+		Kind:        "runtime-kind",
+		Values:      map[string]string{"runtime-kind": "Java", "runtime-kind-version": "openjdk 17.0.11"},
+		Confidence:  "medium",
+	})
+	writeResult(t, dir, "runtime-kind.java-version.2.json", Result{
+		Version:     1,
+		Fingerprint: "java-version",
+		Kind:        "runtime-kind",
+		Values:      map[string]string{"runtime-kind": "Java", "runtime-kind-version": "17.0.11"},
+		Evidence:    []Evidence{{Type: "file", Path: "/usr/lib/jvm/java-17/release", Detail: "JAVA_VERSION"}},
+		Confidence:  "high",
+	})
+	// same confidence as kind-executable but lower fingerprint precedence
+	writeResult(t, dir, "runtime-kind.other.0.json", Result{
+		Version:     1,
+		Fingerprint: "other",
+		Kind:        "runtime-kind",
+		Values:      map[string]string{"runtime-kind": "Other"},
+		Confidence:  "medium",
+	})
+
+	values, err := Read(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"runtime-kind": "Java", "runtime-kind-version": "17.0.11"}, values["runtime-kind"])
+
+	os.Remove(filepath.Join(dir, "runtime-kind.java-version.2.json"))
+	values, err = Read(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"runtime-kind": "Java", "runtime-kind-version": "openjdk 17.0.11"}, values["runtime-kind"])
+}
+
+func TestReadMergesRuntimeComponents(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, "agents-fingerprints.agents.1.json", Result{
+		Version:     1,
+		Fingerprint: "agents",
+		Kind:        "agents-fingerprints",
+		Values:      map[string]string{"OpenTelemetry": ""},
+		Confidence:  "medium",
+	})
+	writeResult(t, dir, "agents-fingerprints.agents.2.json", Result{
+		Version:     1,
+		Fingerprint: "agents",
+		Kind:        "agents-fingerprints",
+		Values:      map[string]string{"OpenTelemetry": "1.32.0"},
+		Confidence:  "high",
+	})
+	writeResult(t, dir, "agents-fingerprints.agents.3.json", Result{
+		Version:     1,
+		Fingerprint: "agents",
+		Kind:        "agents-fingerprints",
+		Values:      map[string]string{"Datadog": "5.1.0"},
+		Confidence:  "high",
+	})
+
+	values, err := Read(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"OpenTelemetry": "1.32.0", "Datadog": "5.1.0"}, values["agents-fingerprints"])
+}
+
+func TestReadIsDeterministic(t *testing.T) {
+	// the suffixes of the result files are random: the selected values do not depend on them
+	for _, suffixes := range [][]string{{"1", "2"}, {"2", "1"}} {
+		dir := t.TempDir()
+		writeResult(t, dir, "agents-fingerprints.agents."+suffixes[0]+".json", Result{
+			Version:     1,
+			Fingerprint: "agents",
+			Kind:        "agents-fingerprints",
+			Values:      map[string]string{"OpenTelemetry": "1.32.0"},
+			Confidence:  "medium",
+		})
+		writeResult(t, dir, "agents-fingerprints.agents."+suffixes[1]+".json", Result{
+			Version:     1,
+			Fingerprint: "agents",
+			Kind:        "agents-fingerprints",
+			Values:      map[string]string{"OpenTelemetry": "1.31.0"},
+			Confidence:  "medium",
+		})
+		writeResult(t, dir, "runtime-kind.zeta."+suffixes[0]+".json", Result{
+			Version:     1,
+			Fingerprint: "zeta",
+			Kind:        "runtime-kind",
+			Values:      map[string]string{"runtime-kind": "Zeta"},
+			Confidence:  "low",
+		})
+		writeResult(t, dir, "runtime-kind.alpha."+suffixes[1]+".json", Result{
+			Version:     1,
+			Fingerprint: "alpha",
+			Kind:        "runtime-kind",
+			Values:      map[string]string{"runtime-kind": "Alpha"},
+			Confidence:  "low",
+		})
+
+		values, err := Read(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"OpenTelemetry": "1.31.0"}, values["agents-fingerprints"])
+		assert.Equal(t, map[string]string{"runtime-kind": "Alpha"}, values["runtime-kind"])
+	}
+}
+
+func TestReadIgnoresInvalidResults(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, "os.os.1.json", Result{
+		Version:     2,
+		Fingerprint: "os",
+		Kind:        "os",
+		Values:      map[string]string{"os-release-id": "rhel"},
+		Confidence:  "high",
+	})
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "os.os.2.json"), []byte("{"), 0644))
+
+	values, err := Read(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
+
+func TestDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	writeResult(t, dir, "diagnostic.os.1.json", Result{
+		Version:     1,
+		Fingerprint: "os",
+		Kind:        "diagnostic",
+		Values:      map[string]string{"status": "ok", "duration-ms": "3", "results": "2", "bytes-read": "512", "zip-entries": "0", "nested-jar-depth": "0"},
+		Confidence:  "high",
+	})
+	writeResult(t, dir, "diagnostic.java-runtimes.2.json", Result{
+		Version:     1,
+		Fingerprint: "java-runtimes",
+		Kind:        "diagnostic",
+		Values: map[string]string{
+			"status": "budget-exceeded", "error": "budget exceeded: max-zip-entries of /app.jar (limit 10)", "exceeded-budgets": "max-zip-entries",
+			"duration-ms": "12", "results": "0", "bytes-read": "0", "zip-entries": "11", "nested-jar-depth": "0",
+		},
+		Confidence: "high",
+	})
+	// written by the extractor for a fingerprint that crashed
+	writeResult(t, dir, "diagnostic.crypto.extractor.json", Result{
+		Version:     1,
+		Fingerprint: "crypto",
+		Kind:        "diagnostic",
+		Values:      map[string]string{"status": "crashed", "error": "signal: killed"},
+		Confidence:  "high",
+	})
+
+	diagnostics, err := Diagnostics(false, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.FingerprintDiagnostic{
+		{Fingerprint: "crypto", Status: "crashed", Error: "signal: killed"},
+		{
+			Fingerprint: "java-runtimes", Status: "budget-exceeded", Error: "budget exceeded: max-zip-entries of /app.jar (limit 10)",
+			DurationMs: 12, ZipEntries: 11, ExceededBudgets: []string{"max-zip-entries"},
+		},
+		{Fingerprint: "os", Status: "ok", DurationMs: 3, Results: 2, BytesRead: 512},
+	}, diagnostics)
+
+	// the error messages are hashed
+	diagnostics, err = Diagnostics(true, dir)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "signal: killed", diagnostics[0].Error)
+	assert.Equal(t, "crashed", diagnostics[0].Status)
+
+	// the diagnostics are not runtime information
+	values, err := Read(dir)
+	assert.NoError(t, err)
+	assert.NotContains(t, values, "diagnostic")
+}
+
+func TestRuntimeInfoOsPackages(t *testing.T) {
+	runtimeInfo := RuntimeInfo(false, map[string]map[string]string{
+		"os-packages": {
+			"zlib:x86_64":        "1.2.11-40.el9",
+			"glibc:x86_64":       "2.34-100.el9",
+			"glibc:i686":         "2.34-100.el9",
+			"kernel-core:x86_64": "5.14.0-427.16.1.el9_4,5.14.0-427.13.1.el9_4",
+			"ca-certificates":    "20240203",
+		},
+	})
+	assert.Equal(t, []types.OsPackage{
+		{Name: "ca-certificates", Version: "20240203"},
+		{Name: "glibc", Version: "2.34-100.el9", Arch: "i686"},
+		{Name: "glibc", Version: "2.34-100.el9", Arch: "x86_64"},
+		{Name: "kernel-core", Version: "5.14.0-427.13.1.el9_4", Arch: "x86_64"},
+		{Name: "kernel-core", Version: "5.14.0-427.16.1.el9_4", Arch: "x86_64"},
+		{Name: "zlib", Version: "1.2.11-40.el9", Arch: "x86_64"},
+	}, runtimeInfo.OsPackages)
+}
+
+func TestRuntimeInfoPostureFindings(t *testing.T) {
+	runtimeInfo := RuntimeInfo(false, map[string]map[string]string{
+		"posture": {"node-inspector": "--inspect", "node-env-development": "", "java-jdwp-agent": "transport=dt_socket"},
+	})
+	assert.Equal(t, []types.PostureFinding{
+		{Name: "java-jdwp-agent", Detail: "transport=dt_socket"},
+		{Name: "node-env-development"},
+		{Name: "node-inspector", Detail: "--inspect"},
+	}, runtimeInfo.PostureFindings)
+}
@@ -0,0 +1,169 @@
+package results
+
+import (
+	"crypto/sha256"
+	"hash"
+	"log"
+	"sort"
+	"strings"
+
+	"exporter/pkg/types"
+	"exporter/pkg/utils"
+)
+
+// kinds of the runtime components based on the kind of their fingerprint results
+var runtimeComponentKinds = map[string]string{
+	"agents-fingerprints": "agent",
+}
+
+// RuntimeInfo returns the runtime information of a container from its fingerprint results (as returned by Read).
+// If hash is true, the values are hashed.
+func RuntimeInfo(hash bool, fingerprints map[string]map[string]string) types.ContainerRuntimeInfo {
+	h := sha256.New()
+	runtimeInfo := types.ContainerRuntimeInfo{}
+	// Operating System fingerprint
+	if info, exists := fingerprints["os"]; exists {
+		runtimeInfo.Os = utils.HashString(hash, h, info["os-release-id"])
+		runtimeInfo.OsVersion = utils.HashString(hash, h, info["os-release-version-id"])
+		runtimeInfo.OsIDLike = utils.HashString(hash, h, info["os-release-id-like"])
+		runtimeInfo.OsPrettyName = utils.HashString(hash, h, info["os-release-pretty-name"])
+		runtimeInfo.OsVersionCodename = utils.HashString(hash, h, info["os-release-version-codename"])
+		runtimeInfo.OsCPEName = utils.HashString(hash, h, info["os-release-cpe-name"])
+	}
+	// the packages installed in the Operating System
+	if info, exists := fingerprints["os-packages"]; exists {
+		for _, pkg := range osPackages(info) {
+			runtimeInfo.OsPackages = append(runtimeInfo.OsPackages, types.OsPackage{
+				Name:    utils.HashString(hash, h, pkg.Name),
+				Version: utils.HashString(hash, h, pkg.Version),
+				Arch:    utils.HashString(hash, h, pkg.Arch),
+			})
+		}
+	}
+	// the Red Hat base image fingerprint
+	if info, exists := fingerprints["base-image"]; exists {
+		runtimeInfo.BaseImage = &types.BaseImageInfo{
+			Name:        utils.HashString(hash, h, info["base-image-name"]),
+			Component:   utils.HashString(hash, h, info["base-image-component"]),
+			Version:     utils.HashString(hash, h, info["base-image-version"]),
+			ContentSets: hashList(hash, h, info["base-image-content-sets"]),
+		}
+	}
+	// the Runtime Kind fingerprint
+	if info, exists := fingerprints["runtime-kind"]; exists {
+		runtimeInfo.Kind = utils.HashString(hash, h, info["runtime-kind"])
+		runtimeInfo.KindVersion = utils.HashString(hash, h, info["runtime-kind-version"])
+		runtimeInfo.KindImplementer = utils.HashString(hash, h, info["runtime-kind-implementer"])
+		if imageType, exists := info["java-image-type"]; exists {
+			runtimeInfo.JavaRuntime = &types.JavaRuntimeInfo{
+				JvmVariant:    utils.HashString(hash, h, info["java-jvm-variant"]),
+				ImageType:     utils.HashString(hash, h, imageType),
+				CDSArchive:    info["java-cds-archive"] == "true",
+				VendorVersion: utils.HashString(hash, h, info["java-vendor-version"]),
+			}
+		}
+	}
+
+	// the Java release targeted by the application
+	if info, exists := fingerprints["java-bytecode"]; exists {
+		runtimeInfo.KindBytecodeVersion = utils.HashString(hash, h, info["java-bytecode-version"])
+	}
+
+	// the resource configuration of the JVM
+	if info, exists := fingerprints["java-options"]; exists {
+		runtimeInfo.JvmConfiguration = &types.JvmConfiguration{
+			MaxHeap:                   utils.HashString(hash, h, info["java-max-heap"]),
+			MaxRAMPercentage:          utils.HashString(hash, h, info["java-max-ram-percentage"]),
+			GC:                        utils.HashString(hash, h, info["java-gc"]),
+			ContainerSupportDisabled:  info["java-container-support"] == "false",
+			ActiveProcessorCount:      utils.HashString(hash, h, info["java-active-processor-count"]),
+			ContainerMemoryLimit:      utils.HashString(hash, h, info["container-memory-limit"]),
+			MaxHeapExceedsMemoryLimit: info["java-max-heap-exceeds-memory-limit"] == "true",
+		}
+	}
+
+	// the namespace of the Java EE APIs
+	if info, exists := fingerprints["java-namespace"]; exists {
+		runtimeInfo.JavaEENamespace = utils.HashString(hash, h, info["java-ee-namespace"])
+	}
+
+	// the security posture findings
+	if info, exists := fingerprints["posture"]; exists {
+		names := make([]string, 0, len(info))
+		for k := range info {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			runtimeInfo.PostureFindings = append(runtimeInfo.PostureFindings, types.PostureFinding{
+				Name:   utils.HashString(hash, h, k),
+				Detail: utils.HashString(hash, h, info[k]),
+			})
+		}
+	}
+	// the crypto libraries and FIPS readiness
+	if info, exists := fingerprints["crypto"]; exists {
+		runtimeInfo.Crypto = &types.CryptoInfo{
+			FIPSCapable:           info["fips-capable"] == "true",
+			FIPSEvidence:          hashList(hash, h, info["fips-evidence"]),
+			OpenSSLVersion:        utils.HashString(hash, h, info["openssl-version"]),
+			CryptoPolicy:          utils.HashString(hash, h, info["crypto-policy"]),
+			JavaSecurityProviders: hashList(hash, h, info["java-security-providers"]),
+			GoFIPSBuildTags:       hashList(hash, h, info["go-fips-build-tags"]),
+		}
+	}
+
+	// Read all other fingerprints results to fill the runtimes map
+	for kind, info := range fingerprints {
+		if !strings.HasSuffix(kind, "-fingerprints") {
+			continue
+		}
+		log.Println("Got fingerprints results ", kind)
+		for k, v := range info {
+			log.Println("Got key=value", k, v)
+
+			runtimeInfo.Runtimes = append(runtimeInfo.Runtimes, types.RuntimeComponent{
+				Name:    utils.HashString(hash, h, k),
+				Version: utils.HashString(hash, h, v),
+				Kind:    runtimeComponentKinds[kind],
+			})
+		}
+	}
+
+	return runtimeInfo
+}
+
+// osPackages returns the packages of the os-packages values, sorted by name, version and architecture.
+// The keys are `<name>:<arch>` (or the name only) and the values are comma-separated lists of versions.
+func osPackages(info map[string]string) []types.OsPackage {
+	packages := []types.OsPackage{}
+	for k, v := range info {
+		name, arch, _ := strings.Cut(k, ":")
+		for _, version := range strings.Split(v, ",") {
+			packages = append(packages, types.OsPackage{Name: name, Version: version, Arch: arch})
+		}
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.Arch < b.Arch
+	})
+	return packages
+}
+
+// hashList hashes each value of a comma-separated list
+func hashList(hash bool, h hash.Hash, list string) []string {
+	if list == "" {
+		return nil
+	}
+	values := strings.Split(list, ",")
+	for i, value := range values {
+		values[i] = utils.HashString(hash, h, value)
+	}
+	return values
+}
@@ -10,14 +10,72 @@ type ContainerRuntimeInfo struct {
 	Os string `json:"os,omitempty"`
 	// Hash of the version identifier of the Operating System (based on /etc/os-release VERSION_ID)
 	OsVersion string `json:"osVersion,omitempty"`
+	// Identifiers of the Operating Systems the Operating System is derived from (based on /etc/os-release ID_LIKE)
+	OsIDLike string `json:"osIdLike,omitempty"`
+	// Name of the Operating System for presentation (based on /etc/os-release PRETTY_NAME)
+	OsPrettyName string `json:"osPrettyName,omitempty"`
+	// Codename of the version of the Operating System (based on /etc/os-release VERSION_CODENAME)
+	OsVersionCodename string `json:"osVersionCodename,omitempty"`
+	// CPE name of the Operating System (based on /etc/os-release CPE_NAME)
+	OsCPEName string `json:"osCpeName,omitempty"`
+	// Packages installed by the package manager of the Operating System (filtered by an allow-list)
+	OsPackages []OsPackage `json:"osPackages,omitempty"`
+	// Red Hat base image of the container image (based on /root/buildinfo)
+	BaseImage *BaseImageInfo `json:"baseImage,omitempty"`
 	// Identifier of the kind of runtime
 	Kind string `json:"kind,omitempty"`
 	// Version of the kind of runtime
 	KindVersion string `json:"kindVersion,omitempty"`
+	// Java release targeted by the bytecode of the application main class (only set if the runtime kind is Java)
+	KindBytecodeVersion string `json:"kindBytecodeVersion,omitempty"`
 	// Entity that provides the runtime-kind implementation
 	KindImplementer string `json:"kindImplementer,omitempty"`
+	// Profile of the Java runtime image (only set if the runtime kind is Java)
+	JavaRuntime *JavaRuntimeInfo `json:"javaRuntime,omitempty"`
+	// Resource configuration of the JVM (only set if the runtime kind is Java)
+	JvmConfiguration *JvmConfiguration `json:"jvmConfiguration,omitempty"`
+	// Namespace of the Java EE APIs used by the application (javax, jakarta or mixed)
+	JavaEENamespace string `json:"javaEENamespace,omitempty"`
 	// Runtimes components
 	Runtimes []RuntimeComponent `json:"runtimes,omitempty"`
+	// Risky configurations detected from the launch configuration of the process
+	PostureFindings []PostureFinding `json:"postureFindings,omitempty"`
+	// Crypto libraries and FIPS readiness of the container
+	Crypto *CryptoInfo `json:"crypto,omitempty"`
+	// How the fingerprints ran for the container (only set if the diagnostics are requested)
+	Diagnostics []FingerprintDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type OsPackage struct {
+	// Name of the package
+	Name string `json:"name"`
+	// Version of the package
+	Version string `json:"version,omitempty"`
+	// Architecture of the package
+	Arch string `json:"arch,omitempty"`
+}
+
+type BaseImageInfo struct {
+	// Name of the base image (for example ubi9/ubi-minimal)
+	Name string `json:"name"`
+	// Brew component of the base image (for example ubi9-minimal-container)
+	Component string `json:"component,omitempty"`
+	// Version and release of the base image (for example 9.4-1194)
+	Version string `json:"version,omitempty"`
+	// Content sets (RPM repositories) used to build the layers of the container image
+	ContentSets []string `json:"contentSets,omitempty"`
+}
+
+// JavaRuntimeInfo represents the profile of the Java runtime image that runs the application.
+type JavaRuntimeInfo struct {
+	// Implementation of the JVM (HotSpot or OpenJ9)
+	JvmVariant string `json:"jvmVariant,omitempty"`
+	// Type of the runtime image (JDK, JRE or jlink)
+	ImageType string `json:"imageType,omitempty"`
+	// Whether the runtime image contains a Class Data Sharing archive
+	CDSArchive bool `json:"cdsArchive"`
+	// Build version of the runtime as set by its vendor
+	VendorVersion string `json:"vendorVersion,omitempty"`
 }
 
 type RuntimeComponent struct {
@@ -25,4 +83,69 @@ type RuntimeComponent struct {
 	Name string `json:"name,omitempty"`
 	// The version of this runtime
 	Version string `json:"version,omitempty"`
+	// The kind of this runtime component (for example "agent" for monitoring agents)
+	Kind string `json:"kind,omitempty"`
+}
+
+// JvmConfiguration represents the resource configuration of the JVM from its effective options
+type JvmConfiguration struct {
+	// Maximum heap size (-Xmx or -XX:MaxHeapSize)
+	MaxHeap string `json:"maxHeap,omitempty"`
+	// Maximum heap size as a percentage of the available memory (-XX:MaxRAMPercentage)
+	MaxRAMPercentage string `json:"maxRAMPercentage,omitempty"`
+	// Garbage collector algorithm (G1, Parallel, Serial, ZGC, Shenandoah, CMS or Epsilon)
+	GC string `json:"gc,omitempty"`
+	// Whether the container support is disabled (-XX:-UseContainerSupport)
+	ContainerSupportDisabled bool `json:"containerSupportDisabled"`
+	// Number of CPUs used by the JVM (-XX:ActiveProcessorCount)
+	ActiveProcessorCount string `json:"activeProcessorCount,omitempty"`
+	// Memory limit (in bytes) of the container
+	ContainerMemoryLimit string `json:"containerMemoryLimit,omitempty"`
+	// Whether the maximum heap size is larger than the memory limit of the container
+	MaxHeapExceedsMemoryLimit bool `json:"maxHeapExceedsMemoryLimit"`
+}
+
+type PostureFinding struct {
+	// Identifier of the risky configuration (for example "java-jdwp-agent")
+	Name string `json:"name"`
+	// Details of the configuration (for example the address of the debug agent)
+	Detail string `json:"detail,omitempty"`
+}
+
+type CryptoInfo struct {
+	// Whether the crypto module used by the runtime of the process can operate in FIPS mode
+	FIPSCapable bool `json:"fipsCapable"`
+	// Evidence supporting the FIPS assessment (for example "openssl-fips-provider" or "go-boringcrypto")
+	FIPSEvidence []string `json:"fipsEvidence,omitempty"`
+	// Version of OpenSSL (for example 3.0.7)
+	OpenSSLVersion string `json:"opensslVersion,omitempty"`
+	// System-wide crypto policy (for example DEFAULT or FIPS)
+	CryptoPolicy string `json:"cryptoPolicy,omitempty"`
+	// Security providers configured in the java.security file (only set if the runtime kind is Java)
+	JavaSecurityProviders []string `json:"javaSecurityProviders,omitempty"`
+	// FIPS related build tags of the Go executable (only set if the runtime kind is Golang)
+	GoFIPSBuildTags []string `json:"goFipsBuildTags,omitempty"`
+}
+
+// FingerprintDiagnostic describes how a fingerprint ran for the container
+// so that a fingerprint that detected nothing can be told apart from a fingerprint that failed.
+type FingerprintDiagnostic struct {
+	// Name of the fingerprint
+	Fingerprint string `json:"fingerprint"`
+	// Status of the fingerprint: ok, failed, budget-exceeded or crashed
+	Status string `json:"status"`
+	// Error message of the fingerprint (hashed like the other values)
+	Error string `json:"error,omitempty"`
+	// Duration of the fingerprint in milliseconds
+	DurationMs int64 `json:"durationMs"`
+	// Number of results written by the fingerprint
+	Results int `json:"results"`
+	// Number of bytes read from the files of the container
+	BytesRead int64 `json:"bytesRead"`
+	// Number of entries of the zip files opened
+	ZipEntries int `json:"zipEntries"`
+	// Deepest nested jar read
+	NestedJarDepth int `json:"nestedJarDepth"`
+	// Names of the resource budgets exceeded by the fingerprint (for example timeout or max-file-bytes)
+	ExceededBudgets []string `json:"exceededBudgets,omitempty"`
 }
@@ -40,7 +40,7 @@ func TestNodeJS(t *testing.T) {
 			g.Expect(result.Os).Should(Ω.Equal("alpine"))
 			g.Expect(result.OsVersion).Should(Ω.Equal("3.20.2"))
 			g.Expect(result.Kind).Should(Ω.Equal("Node.js"))
-			g.Expect(result.KindVersion).Should(Ω.Equal("v22.6.0"))
+			g.Expect(result.KindVersion).Should(Ω.Equal("22.6.0"))
 			g.Expect(result.KindImplementer).Should(Ω.BeEmpty())
 
 			return ctx
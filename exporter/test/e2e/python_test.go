@@ -43,7 +43,7 @@ func TestPython3(t *testing.T) {
 			g.Expect(result.Os).Should(Ω.Equal("debian"))
 			g.Expect(result.OsVersion).Should(Ω.Equal("12"))
 			g.Expect(result.Kind).Should(Ω.Equal("Python"))
-			g.Expect(result.KindVersion).Should(Ω.Equal("Python 3.9.19"))
+			g.Expect(result.KindVersion).Should(Ω.Equal("3.9.19"))
 			g.Expect(result.KindImplementer).Should(Ω.BeEmpty())
 
 			g.Expect(len(result.Runtimes)).To(Ω.Equal(0))
@@ -1,5 +1,5 @@
 use clap::Parser;
-use log::{error, info, trace};
+use log::{error, info, trace, warn};
 use std::fs;
 use std::io::Write;
 use std::net::{Shutdown, TcpListener, TcpStream};
@@ -31,10 +31,17 @@ fn main() {
 
     perms::check_privileged_perms().expect("Must have privileged permissions to scan containers");
 
-    // verify that the configuration is properly setup
-    let config_content = fs::read_to_string("/config.toml").expect("Configuration file is missing");
-    info!("Configuration:\n----\n{}\n----", config_content);
+    // verify that the configuration (and its config.d drop-in files) is properly setup
+    for file in config::config_files("/") {
+        let config_content = fs::read_to_string(&file).expect("Configuration file is missing");
+        info!(
+            "Configuration {}:\n----\n{}\n----",
+            file.display(),
+            config_content
+        );
+    }
     config::get_config("/");
+    validate_config();
 
     // Create a TCP listener
     // bound to the loopback address so that it can only be contacted
@@ -54,6 +61,24 @@ fn main() {
     }
 }
 
+/// Report the problems of the configuration (unknown keys, invalid regexes...) found by `fpr validate`.
+/// A configuration with problems is still used (its invalid entries do not detect anything).
+fn validate_config() {
+    match Command::new("/fpr")
+        .arg("validate")
+        .arg("/config.toml")
+        .output()
+    {
+        Ok(output) if output.status.success() => info!("Configuration is valid"),
+        Ok(output) => warn!(
+            "Configuration has problems:\n{}{}",
+            String::from_utf8_lossy(&output.stdout),
+            String::from_utf8_lossy(&output.stderr)
+        ),
+        Err(err) => warn!("Unable to validate the configuration: {}", err),
+    }
+}
+
 fn handle_trigger_extraction(mut stream: TcpStream) {
     info!("Triggering new runtime info extraction");
 
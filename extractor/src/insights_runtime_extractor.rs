@@ -12,7 +12,7 @@ use nix::unistd::{fork, seteuid, ForkResult};
 use process::ContainerProcess;
 use serde::Serialize;
 use std::collections::HashMap;
-use std::fs::{self, File};
+use std::fs::File;
 use std::os::fd::{AsFd, AsRawFd, BorrowedFd};
 use std::path::Path;
 use std::time::Instant;
@@ -96,10 +96,14 @@ pub fn scan_container(config: &Config, out: &String, container: &Container) {
             &container_info,
         );
 
-        // copy the config.toml to the pid_output so that it can be read by fingerprints executables
-        fs::copy("/config.toml", container_output.clone() + "/config.toml")
+        // copy the config.toml (and its config.d drop-in files) to the pid_output so that it can be read by fingerprints executables
+        config::copy_config("/", &container_output)
             .ok()
             .expect("Copy configuration for fingerprints execution");
+        // write the process context that the fingerprints executables read alongside the configuration
+        process::write_process_context(process, &container_output)
+            .ok()
+            .expect("Write process context for fingerprints execution");
 
         let start = Instant::now();
 
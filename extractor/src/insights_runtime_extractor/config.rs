@@ -1,5 +1,22 @@
 use serde::Deserialize;
 use std::fs;
+use std::io;
+use std::path::{Path, PathBuf};
+use toml::{Table, Value};
+
+/// Name of the directory of the drop-in configuration files, alongside `config.toml`
+pub const CONFIG_DROP_IN_DIR: &str = "config.d";
+
+/// Keys identifying the entries of the arrays of tables of the configuration.
+/// An entry of a drop-in file replaces the entry of the configuration that has the same identity
+/// (like the `configEntryIdentities` of the fingerprints that read the same files).
+const ENTRY_IDENTITIES: [(&str, &str); 5] = [
+    ("version-executables", "runtime-kind-name"),
+    ("java", "runtime-name"),
+    ("jvm-languages", "runtime-name"),
+    ("agents", "agent-name"),
+    ("file-rules", "runtime-name"),
+];
 
 #[derive(Deserialize, Debug)]
 pub struct Config {
@@ -13,6 +30,9 @@ pub struct Fingerprints {
     pub versioned_executables: Vec<VersionExecutables>,
 
     pub java: Vec<JavaFingerprint>,
+
+    #[serde(rename = "file-rules", default)]
+    pub file_rules: Vec<FileRule>,
 }
 
 #[derive(Deserialize, Debug)]
@@ -37,8 +57,152 @@ pub struct JavaFingerprint {
     pub jar_version_manifest_entry: String,
 }
 
+/// File rule evaluated by the `file-rules` fingerprint.
+/// Only the processes that the rule applies to are read by the extractor.
+#[derive(Deserialize, Debug)]
+pub struct FileRule {
+    #[serde(rename = "process-names", default)]
+    pub process_names: Vec<String>,
+}
+
+/// Files of the configuration of the directory: `config.toml` followed by
+/// the drop-in files (`*.toml`) of its `config.d` directory, sorted by name
+pub fn config_files(dir: &str) -> Vec<PathBuf> {
+    let mut drop_ins: Vec<PathBuf> = fs::read_dir(Path::new(dir).join(CONFIG_DROP_IN_DIR))
+        .map(|entries| {
+            entries
+                .filter_map(|entry| entry.ok())
+                .map(|entry| entry.path())
+                .filter(|path| {
+                    path.extension().map_or(false, |ext| ext == "toml") && path.is_file()
+                })
+                .collect()
+        })
+        .unwrap_or_default();
+    drop_ins.sort();
+
+    let mut files = vec![Path::new(dir).join("config.toml")];
+    files.append(&mut drop_ins);
+    files
+}
+
+/// Copy the configuration files of the directory (see `config_files`) to the `out` directory
+/// so that the fingerprints read the same configuration
+pub fn copy_config(dir: &str, out: &str) -> io::Result<()> {
+    for file in config_files(dir) {
+        let target = Path::new(out).join(file.strip_prefix(dir).unwrap_or(&file));
+        if let Some(parent) = target.parent() {
+            fs::create_dir_all(parent)?;
+        }
+        fs::copy(&file, target)?;
+    }
+    Ok(())
+}
+
+/// Read the configuration of the directory and merge its drop-in files into it (see `config_files`).
+///
+/// The tables are merged key by key, the values of a drop-in file replace the values of the configuration,
+/// and the entries of the arrays of tables replace the entries with the same identity or are appended.
 pub fn get_config(dir: &str) -> Config {
-    let config_content =
-        fs::read_to_string(dir.to_owned() + "/config.toml").expect("Configuration file is missing");
-    toml::from_str(&config_content).expect("unable to read configuration file")
+    let mut merged = Table::new();
+    for file in config_files(dir) {
+        let config_content = fs::read_to_string(&file).expect("Configuration file is missing");
+        let document: Table = toml::from_str(&config_content).expect(&format!(
+            "unable to read configuration file {}",
+            file.display()
+        ));
+        merge_config(&mut merged, document);
+    }
+    Value::Table(merged)
+        .try_into()
+        .expect("unable to read configuration file")
+}
+
+fn merge_config(base: &mut Table, drop_in: Table) {
+    for (key, value) in drop_in {
+        let identity = ENTRY_IDENTITIES
+            .iter()
+            .find(|(array, _)| *array == key)
+            .map(|(_, identity)| *identity);
+        match (base.get_mut(&key), value, identity) {
+            (Some(Value::Table(base_table)), Value::Table(table), _) => {
+                merge_config(base_table, table)
+            }
+            (Some(Value::Array(base_entries)), Value::Array(entries), Some(identity)) => {
+                merge_config_entries(base_entries, entries, identity)
+            }
+            (_, value, _) => {
+                base.insert(key, value);
+            }
+        }
+    }
+}
+
+fn merge_config_entries(base: &mut Vec<Value>, drop_in: Vec<Value>, identity: &str) {
+    for entry in drop_in {
+        let id = entry.get(identity).cloned();
+        match base
+            .iter()
+            .position(|base_entry| id.is_some() && base_entry.get(identity) == id.as_ref())
+        {
+            Some(idx) => base[idx] = entry,
+            None => base.push(entry),
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn it_merges_drop_in_files() {
+        let dir = std::env::temp_dir().join(format!("config-{}", std::process::id()));
+        fs::create_dir_all(dir.join(CONFIG_DROP_IN_DIR)).unwrap();
+        fs::write(
+            dir.join("config.toml"),
+            r#"
+[[fingerprints.version-executables]]
+process-names = ["node"]
+runtime-kind-name = "Node.js"
+
+[[fingerprints.java]]
+runtime-name = "Quarkus"
+main-class = "io.quarkus.bootstrap.runner.QuarkusEntryPoint"
+read-manifest-of-executable-jar = false
+jar-version-manifest-entry = "Implementation-Version"
+"#,
+        )
+        .unwrap();
+        fs::write(
+            dir.join(CONFIG_DROP_IN_DIR).join("10-site.toml"),
+            r#"
+[[fingerprints.version-executables]]
+process-names = ["node", "nodejs"]
+runtime-kind-name = "Node.js"
+
+[[fingerprints.version-executables]]
+process-names = ["nginx"]
+runtime-kind-name = "nginx"
+
+[[fingerprints.file-rules]]
+runtime-name = "PostgreSQL"
+process-names = ["postgres"]
+"#,
+        )
+        .unwrap();
+
+        let config = get_config(dir.to_str().unwrap());
+        fs::remove_dir_all(&dir).unwrap();
+
+        let executables = &config.fingerprints.versioned_executables;
+        assert_eq!(2, executables.len());
+        assert_eq!(vec!["node", "nodejs"], executables[0].process_names);
+        assert_eq!("nginx", executables[1].runtime_kind_name);
+        assert_eq!(1, config.fingerprints.java.len());
+        assert_eq!(
+            vec!["postgres"],
+            config.fingerprints.file_rules[0].process_names
+        );
+    }
 }
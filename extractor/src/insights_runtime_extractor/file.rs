@@ -23,28 +23,37 @@ pub fn create_dir(name: &str) -> io::Result<File> {
     File::open(&name)
 }
 
-/// Read a key=value file and return its content in a HashMap.
+/// Escape a key or a value of a key=value file.
 ///
-/// Key and values are separated by '='.
-pub fn read_key_value_file(path: &str) -> io::Result<HashMap<String, String>> {
-    debug!("📄  Reading key=value file {:?}", path);
-
-    let content = fs::read_to_string(path)?;
-    let mut map = HashMap::new();
-
-    for line in content.lines() {
-        let parts: Vec<&str> = line.splitn(2, '=').collect();
-        if parts.len() == 2 {
-            let key = parts[0].trim().to_string();
-            let value = parts[1].trim_matches('"').to_string();
-            map.insert(key, value);
+/// The escaping scheme is the one of the `keyvalue` Go module that reads the file:
+/// `\\`, `\n`, `\r`, `\t`, `\=`, `\#`, `\"` and `\'` escape the corresponding characters
+/// and `\xHH` escapes the other control characters and the spaces at the start or the end.
+pub fn escape(s: &str) -> String {
+    let mut escaped = String::with_capacity(s.len());
+    let last = s.chars().count().saturating_sub(1);
+    for (i, c) in s.chars().enumerate() {
+        match c {
+            '\\' => escaped.push_str("\\\\"),
+            '\n' => escaped.push_str("\\n"),
+            '\r' => escaped.push_str("\\r"),
+            '\t' => escaped.push_str("\\t"),
+            '=' | '#' | '"' | '\'' => {
+                escaped.push('\\');
+                escaped.push(c);
+            }
+            ' ' if i == 0 || i == last => escaped.push_str("\\x20"),
+            c if (c as u32) < 0x20 || c as u32 == 0x7F => {
+                escaped.push_str(&format!("\\x{:02X}", c as u32))
+            }
+            c => escaped.push(c),
         }
     }
-
-    Ok(map)
+    escaped
 }
 
 /// Write entries to a file in the `out` directory.
+///
+/// The keys and the values are escaped (see `escape`).
 pub fn write_entries(
     out: &Path,
     file_name: &str,
@@ -63,7 +72,7 @@ pub fn write_entries(
 
     for key in keys {
         if let Some(value) = entries.get(key) {
-            writeln!(&mut file, "{}={}", key, value)?;
+            writeln!(&mut file, "{}={}", escape(key), escape(value))?;
         }
     }
 
@@ -105,4 +114,16 @@ foo=value1
 
         let _ = fs::remove_dir_all(dir_path);
     }
+
+    #[test]
+    fn it_escapes_entries() {
+        assert_eq!(escape("cri-o://1234"), "cri-o://1234");
+        assert_eq!(escape("v20.11.1\n"), "v20.11.1\\n");
+        assert_eq!(escape("a=b # c"), "a\\=b \\# c");
+        assert_eq!(escape("\"quoted\" 'value'"), "\\\"quoted\\\" \\'value\\'");
+        assert_eq!(escape("C:\\path\r\t"), "C:\\\\path\\r\\t");
+        assert_eq!(escape(" padded "), "\\x20padded\\x20");
+        assert_eq!(escape("bell\u{7}"), "bell\\x07");
+        assert_eq!(escape("Équipe"), "Équipe");
+    }
 }
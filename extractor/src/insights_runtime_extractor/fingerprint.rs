@@ -1,12 +1,21 @@
 use log::{debug, trace, warn};
+use std::fs;
+use std::path::Path;
 use std::process::Command;
 
 use crate::config::Config;
 use crate::insights_runtime_extractor::ContainerProcess;
 
+mod agents;
+mod base_image;
+mod crypto;
+mod file_rules;
 mod java;
+mod java_options;
 mod native_executable;
 mod os;
+mod plugins;
+mod posture;
 mod version_executable;
 
 trait FingerPrint {
@@ -18,12 +27,31 @@ trait FingerPrint {
     ) -> Option<Vec<String>>;
 }
 
+/// Command line of a fingerprint of the `fpr` executable.
+///
+/// The fingerprints read the process from the `process.json` process context of the output directory.
+fn fpr(fingerprint: &str, out_dir: &str) -> Vec<String> {
+    vec![
+        String::from("./fpr"),
+        String::from(fingerprint),
+        out_dir.to_string(),
+    ]
+}
+
 fn fingerprints() -> Vec<Box<dyn FingerPrint>> {
     vec![
         Box::new(os::Os {}),
+        Box::new(base_image::BaseImage {}),
         Box::new(version_executable::VersionExecutable {}),
-        Box::new(java::Java {}),
+        Box::new(java::Java::runtimes()),
+        Box::new(java::Java::namespace()),
+        Box::new(java_options::JavaOptions {}),
         Box::new(native_executable::NativeExecutable {}),
+        Box::new(agents::Agents {}),
+        Box::new(file_rules::FileRules {}),
+        Box::new(plugins::Plugins {}),
+        Box::new(posture::Posture {}),
+        Box::new(crypto::Crypto {}),
     ]
 }
 
@@ -47,14 +75,58 @@ pub fn run_fingerprints(config: &Config, out_dir: &String, process: &ContainerPr
                         false => {
                             let error = String::from_utf8_lossy(&output.stderr);
                             warn!("Command {:#?} failed with error:\n{:#?}", exec, error);
+                            let status = match output.status.code() {
+                                Some(code) => format!("exit code {}", code),
+                                // killed by a signal
+                                None => format!("{}", output.status),
+                            };
+                            write_crash_diagnostic(out_dir, &exec, &status);
                         }
                     },
                     Err(e) => {
                         // Print the error if command execution fails
                         warn!("Error executing command {:#?}: {:#?}", exec, e);
+                        write_crash_diagnostic(out_dir, &exec, &e.to_string());
                     }
                 }
             }
         }
     }
 }
+
+/// Write a `crashed` diagnostic for a fingerprint that failed without writing its own diagnostic
+/// (the fpr executable could not run, was killed or exited before running the fingerprint).
+///
+/// The diagnostic has the same format as the diagnostics written by the fingerprints
+/// so that the exporter can tell a crashed fingerprint from a fingerprint that detected nothing.
+fn write_crash_diagnostic(out_dir: &str, exec: &[String], error: &str) {
+    // exec is ["./fpr", "<fingerprint>", ...]
+    let fingerprint = match exec.get(1) {
+        Some(fingerprint) => fingerprint,
+        None => return,
+    };
+    let prefix = format!("diagnostic.{}.", fingerprint);
+    if let Ok(entries) = fs::read_dir(out_dir) {
+        if entries
+            .flatten()
+            .any(|entry| entry.file_name().to_string_lossy().starts_with(&prefix))
+        {
+            return;
+        }
+    }
+
+    let diagnostic = serde_json::json!({
+        "version": 1,
+        "fingerprint": fingerprint,
+        "kind": "diagnostic",
+        "values": {
+            "status": "crashed",
+            "error": error,
+        },
+        "confidence": "high",
+    });
+    let path = Path::new(out_dir).join(format!("{}extractor.json", prefix));
+    if let Err(e) = fs::write(&path, diagnostic.to_string()) {
+        warn!("Unable to write diagnostic {:?}: {}", path, e);
+    }
+}
@@ -0,0 +1,24 @@
+use log::debug;
+
+use super::{fpr, version_executable, FingerPrint};
+use crate::config::Config;
+use crate::insights_runtime_extractor::ContainerProcess;
+
+pub struct Agents {}
+
+impl FingerPrint for Agents {
+    fn can_apply_to(
+        &self,
+        _: &Config,
+        out_dir: &String,
+        process: &ContainerProcess,
+    ) -> Option<Vec<String>> {
+        if !version_executable::is_version_executable(process) {
+            return None;
+        }
+
+        debug!("Checking if {} is instrumented by agents", &process.name);
+
+        Some(fpr("agents", out_dir))
+    }
+}
@@ -0,0 +1,18 @@
+use crate::config::Config;
+use crate::insights_runtime_extractor::ContainerProcess;
+
+use super::{fpr, FingerPrint};
+
+/// Fingerprint the Red Hat base image from the build information in /root/buildinfo
+pub struct BaseImage {}
+
+impl FingerPrint for BaseImage {
+    fn can_apply_to(
+        &self,
+        _config: &Config,
+        out_dir: &String,
+        _process: &ContainerProcess,
+    ) -> Option<Vec<String>> {
+        Some(fpr("base-image", out_dir))
+    }
+}
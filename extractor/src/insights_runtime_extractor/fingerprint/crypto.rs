@@ -0,0 +1,23 @@
+use log::debug;
+
+use super::{fpr, FingerPrint};
+use crate::config::Config;
+use crate::insights_runtime_extractor::ContainerProcess;
+
+/// Fingerprint the crypto libraries and the FIPS readiness of the process
+pub struct Crypto {}
+
+impl FingerPrint for Crypto {
+    fn can_apply_to(
+        &self,
+        _: &Config,
+        out_dir: &String,
+        process: &ContainerProcess,
+    ) -> Option<Vec<String>> {
+        process.command_line.get(0)?;
+
+        debug!("Checking the crypto libraries of {}", &process.name);
+
+        Some(fpr("crypto", out_dir))
+    }
+}
@@ -0,0 +1,31 @@
+use log::debug;
+
+use super::{fpr, FingerPrint};
+use crate::config::Config;
+use crate::insights_runtime_extractor::ContainerProcess;
+
+/// Fingerprint the runtimes detected by the file rules of the configuration
+pub struct FileRules {}
+
+impl FingerPrint for FileRules {
+    fn can_apply_to(
+        &self,
+        config: &Config,
+        out_dir: &String,
+        process: &ContainerProcess,
+    ) -> Option<Vec<String>> {
+        // a rule without process names applies to all the processes
+        if !config
+            .fingerprints
+            .file_rules
+            .iter()
+            .any(|rule| rule.process_names.is_empty() || rule.process_names.contains(&process.name))
+        {
+            return None;
+        }
+
+        debug!("Evaluating the file rules for {}", &process.name);
+
+        Some(fpr("file-rules", out_dir))
+    }
+}
@@ -1,27 +1,28 @@
 use log::debug;
 
-use super::FingerPrint;
+use super::{fpr, FingerPrint};
 use crate::insights_runtime_extractor::Config;
 use crate::insights_runtime_extractor::ContainerProcess;
 
-pub struct Java {}
+/// Java fingerprints inspect the jars of the Java application.
+/// They detect the application (executable jar, classpath and main class) from the command line of the process.
+pub struct Java {
+    fingerprint: &'static str,
+}
 
 impl Java {
-    fn jar_executable(
-        out_dir: &String,
-        process: &ContainerProcess,
-        jar: &str,
-    ) -> Option<Vec<String>> {
-        let jar = match jar {
-            jar if jar.starts_with("/") => jar.to_owned(),
-            jar => format!("{:?}/{}", process.cwd, jar),
-        };
+    /// Fingerprint the Java runtimes (frameworks, application servers, JVM languages) of the application
+    pub fn runtimes() -> Java {
+        Java {
+            fingerprint: "java-runtimes",
+        }
+    }
 
-        return Some(vec![
-            String::from("./fpr_java_runtimes"),
-            out_dir.to_string(),
-            jar.to_string(),
-        ]);
+    /// Fingerprint the namespace (javax or jakarta) of the Java EE APIs used by the application
+    pub fn namespace() -> Java {
+        Java {
+            fingerprint: "java-namespace",
+        }
     }
 }
 
@@ -38,50 +39,7 @@ impl FingerPrint for Java {
 
         debug!("Fingerprint Java application from process: {:#?}", process);
 
-        // check "java -jar" process
-        let exec = process
-            .command_line
-            .iter()
-            .position(|s| s == "-jar")
-            .and_then(|i| process.command_line.get(i + 1))
-            .and_then(|jar| {
-                debug!("Executable jar is {:?}", jar);
-                return Java::jar_executable(&out_dir, process, jar);
-            });
-
-        if exec.is_some() {
-            return exec;
-        }
-
-        // check for Java classpath-based process
-        process
-            .command_line
-            .iter()
-            .position(|s| s == "-classpath" || s == "-cp")
-            .and_then(|i| process.command_line.get(i + 1))
-            .and_then(|classpath| {
-                debug!("java process is using classpath {}", classpath);
-                let mut jars = classpath.split(":");
-
-                // let's find the corresponding jar based on the main class
-                for java_fingerprints_config in _config.fingerprints.java.iter() {
-                    if process
-                        .command_line
-                        .contains(&java_fingerprints_config.main_class)
-                    {
-                        debug!("Detected {} ", java_fingerprints_config.runtime_name);
-                        let found = jars
-                            .find(|jar| {
-                                let main_jar = java_fingerprints_config.main_jar.as_ref().unwrap();
-                                jar.contains(main_jar)
-                            })
-                            .and_then(|jar| return Java::jar_executable(&out_dir, process, &jar));
-                        if found.is_some() {
-                            return found.into();
-                        }
-                    }
-                }
-                None
-            })
+        // a process that runs neither an executable jar nor a classpath is ignored by the fingerprint
+        Some(fpr(self.fingerprint, out_dir))
     }
 }
@@ -0,0 +1,24 @@
+use log::debug;
+
+use super::{fpr, FingerPrint};
+use crate::config::Config;
+use crate::insights_runtime_extractor::ContainerProcess;
+
+pub struct JavaOptions {}
+
+impl FingerPrint for JavaOptions {
+    fn can_apply_to(
+        &self,
+        _: &Config,
+        out_dir: &String,
+        process: &ContainerProcess,
+    ) -> Option<Vec<String>> {
+        if !process.name.ends_with("java") {
+            return None;
+        }
+
+        debug!("Fingerprint JVM options from process: {}", &process.pid);
+
+        Some(fpr("java-options", out_dir))
+    }
+}
@@ -1,7 +1,7 @@
 use crate::config::Config;
 use crate::insights_runtime_extractor::ContainerProcess;
 
-use super::FingerPrint;
+use super::{fpr, FingerPrint};
 
 pub struct Os {}
 
@@ -12,6 +12,6 @@ impl FingerPrint for Os {
         out_dir: &String,
         _process: &ContainerProcess,
     ) -> Option<Vec<String>> {
-        Some(vec![String::from("./fpr_os"), out_dir.to_string()])
+        Some(fpr("os", out_dir))
     }
 }
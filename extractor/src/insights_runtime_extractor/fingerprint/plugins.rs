@@ -0,0 +1,36 @@
+use log::debug;
+use std::fs;
+
+use super::{fpr, FingerPrint};
+use crate::config::Config;
+use crate::insights_runtime_extractor::ContainerProcess;
+
+/// Directory of the fingerprint plugins, alongside the `fpr` executable
+/// (the fingerprints run from the root directory of the extractor)
+const PLUGIN_DIR: &str = "plugins";
+
+/// Fingerprint the runtime components detected by the external fingerprint executables of the plugin directory.
+///
+/// The `plugins` fingerprint matches the manifests of the plugins against the process.
+pub struct Plugins {}
+
+impl FingerPrint for Plugins {
+    fn can_apply_to(
+        &self,
+        _config: &Config,
+        out_dir: &String,
+        process: &ContainerProcess,
+    ) -> Option<Vec<String>> {
+        let has_plugins = fs::read_dir(PLUGIN_DIR)
+            .ok()?
+            .flatten()
+            .any(|entry| entry.path().join("plugin.toml").is_file());
+        if !has_plugins {
+            return None;
+        }
+
+        debug!("Running the fingerprint plugins for {}", &process.name);
+
+        Some(fpr("plugins", out_dir))
+    }
+}
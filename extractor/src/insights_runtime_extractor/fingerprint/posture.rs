@@ -0,0 +1,24 @@
+use log::debug;
+
+use super::{fpr, FingerPrint};
+use crate::config::Config;
+use crate::insights_runtime_extractor::ContainerProcess;
+
+pub struct Posture {}
+
+impl FingerPrint for Posture {
+    fn can_apply_to(
+        &self,
+        _: &Config,
+        out_dir: &String,
+        process: &ContainerProcess,
+    ) -> Option<Vec<String>> {
+        if process.command_line.is_empty() {
+            return None;
+        }
+
+        debug!("Checking the security posture of {}", &process.name);
+
+        Some(fpr("posture", out_dir))
+    }
+}
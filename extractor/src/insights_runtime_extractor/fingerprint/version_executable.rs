@@ -1,6 +1,6 @@
 use log::debug;
 
-use super::FingerPrint;
+use super::{fpr, FingerPrint};
 use crate::config::Config;
 use crate::insights_runtime_extractor::ContainerProcess;
 
@@ -18,30 +18,16 @@ impl FingerPrint for VersionExecutable {
             &process.name
         );
 
-        let fpr_kind_executable = String::from("./fpr_kind_executable");
-
-        if let Some(version_executable) = config
+        if config
             .fingerprints
             .versioned_executables
             .iter()
-            .find(|c| c.process_names.contains(&process.name))
+            .any(|c| c.process_names.contains(&process.name))
         {
-            return Some(vec![
-                fpr_kind_executable,
-                out_dir.to_string(),
-                String::from(&process.command_line[0]),
-                String::from(&version_executable.runtime_kind_name),
-            ]);
+            // the runtime kind is read from the configuration by the fingerprint
+            return Some(fpr("kind-executable", out_dir));
         } else if process.command_line[0].contains("java") {
-            // JAVA_HOME env var can not be set
-            let no_java_home = "".to_string();
-            let java_home = process.environ.get("JAVA_HOME").unwrap_or(&no_java_home);
-            return Some(vec![
-                String::from("./fpr_java_version"),
-                out_dir.to_string(),
-                process.environ.get("PATH").unwrap().to_string(),
-                java_home.to_string(),
-            ]);
+            return Some(fpr("java-version", out_dir));
         }
 
         None
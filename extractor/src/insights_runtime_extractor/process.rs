@@ -1,6 +1,8 @@
 use log::{debug, trace};
 use std::collections::HashMap;
 use std::fs;
+use std::io;
+use std::path::Path;
 use std::time::Instant;
 use sysinfo::{Pid, System, Uid};
 
@@ -44,6 +46,23 @@ pub fn get_process_leaves(pid: &u32) -> Vec<ContainerProcess> {
     res
 }
 
+/// Write the process context to the `process.json` file of the `out` directory.
+///
+/// The fingerprints read the process (its command line, environment, uid and cwd) from that file
+/// (see the `ProcessContext` of the fingerprints Go module) instead of receiving it as arguments.
+pub fn write_process_context(process: &ContainerProcess, out: &str) -> io::Result<()> {
+    let context = serde_json::json!({
+        "version": 1,
+        "pid": process.pid,
+        "uid": *process.uid,
+        "name": process.name,
+        "cwd": process.cwd.clone().unwrap_or_default(),
+        "command-line": process.command_line,
+        "environ": process.environ,
+    });
+    fs::write(Path::new(out).join("process.json"), context.to_string())
+}
+
 fn get_leaves(root_pid: &u32) -> Vec<u32> {
     debug!("Detecting leaves: {:#?}", root_pid);
 
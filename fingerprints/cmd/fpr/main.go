@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fingerprints/pkg/fingerprint"
+	"fingerprints/pkg/utils"
+)
+
+func main() {
+	// The program is called either as:
+	// - fpr <fingerprint> [--root <dir>] <output-dir>
+	// - fpr_<fingerprint> [--root <dir>] <output-dir> (when the program is linked under that name)
+	// The output directory contains the configuration (config.toml) and the process context (process.json).
+	// The --root option runs the fingerprint against the root filesystem in that directory
+	// (for example an extracted image layer) instead of the filesystem of the process.
+	//
+	// It is also called as `fpr validate <config.toml>` to validate a configuration (and its config.d drop-in files).
+	args := os.Args[1:]
+	name, found := strings.CutPrefix(filepath.Base(os.Args[0]), "fpr_")
+	if found {
+		name = strings.ReplaceAll(name, "_", "-")
+	} else {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "--help" {
+			usage()
+			return
+		}
+		name = args[0]
+		args = args[1:]
+	}
+
+	if name == "validate" {
+		os.Exit(validate(args))
+	}
+
+	if err := fingerprint.Run(name, args); err != nil {
+		log.Fatalf("❌ %s\n", err)
+	}
+}
+
+// validate prints the problems of the configuration and returns the exit code of the program
+// (1 if the configuration has problems, 2 if it can not be read)
+func validate(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "usage: fpr validate <config.toml>\n")
+		return 2
+	}
+	problems, err := utils.ValidateConfig(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ unable to read configuration: %s\n", err)
+		return 2
+	}
+	for _, problem := range problems {
+		fmt.Println(problem)
+	}
+	if len(problems) > 0 {
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "✅ %s is valid\n", strings.Join(utils.ConfigFiles(args[0]), ", "))
+	return 0
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: fpr <fingerprint> [--root <dir>] <output-dir>\n       fpr validate <config.toml>\n\nfingerprints:\n")
+	for _, name := range fingerprint.Names() {
+		f, _ := fingerprint.Get(name)
+		fmt.Fprintf(os.Stderr, "  %s\n", fingerprint.Usage(f))
+	}
+}
@@ -6,6 +6,8 @@ require (
 	github.com/BurntSushi/toml v1.4.0
 	github.com/saferwall/elf v0.3.1
 	github.com/stretchr/testify v1.9.0
+	keyvalue v0.0.0
+	rootfs v0.0.0
 )
 
 require (
@@ -16,3 +18,7 @@ require (
 	golang.org/x/sys v0.22.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace keyvalue => ../keyvalue
+
+replace rootfs => ../rootfs
@@ -0,0 +1,148 @@
+package crypto
+
+import (
+	"bufio"
+	"debug/buildinfo"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version string embedded in the OpenSSL libraries (for example "OpenSSL 3.0.7 1 Nov 2022")
+var openSSLVersion = regexp.MustCompile(`OpenSSL (\d+\.\d+\.\d+[a-z]*) +\d{1,2} [A-Z][a-z]{2} \d{4}`)
+
+// Version suffix of the OpenSSL libraries names (for example libcrypto.so.3 or libssl.so.1.1)
+var openSSLLibraryVersion = regexp.MustCompile(`^lib(?:ssl|crypto)\.so\.(\d[\d.]*[a-z]?)$`)
+
+// Section of the OpenSSL configuration that activates the FIPS provider
+// (for example "fips = fips_sect" in the providers section or ".include fipsmodule.cnf")
+var openSSLFIPSConfig = regexp.MustCompile(`(?m)^\s*(?:fips\s*=|\.include\s*=?\s*\S*fipsmodule\.cnf)`)
+
+// Java security providers that are FIPS 140 validated
+var javaFIPSProviders = []string{
+	"com.ibm.crypto.fips.provider.IBMJCEFIPS",
+	"org.bouncycastle.jcajce.provider.BouncyCastleFipsProvider",
+	"BCFIPS",
+	"com.safelogic.cryptocomply.jcajce.provider.CryptoComplyFipsProvider",
+}
+
+// Go build tags and experiments that build the executable against a FIPS validated module
+var goFIPSTags = []string{"boringcrypto", "strictfipsruntime", "opensslcrypto"}
+
+// GetOpenSSLVersion returns the version of OpenSSL from the version string embedded in the library.
+// If no version string is found, the version is derived from the library name.
+func GetOpenSSLVersion(libraryPath string) string {
+	if content, err := os.ReadFile(libraryPath); err == nil {
+		if matches := openSSLVersion.FindSubmatch(content); matches != nil {
+			return string(matches[1])
+		}
+	}
+	return GetOpenSSLLibraryVersion(libraryPath)
+}
+
+// GetOpenSSLLibraryVersion returns the version in the name of the OpenSSL library (for example 3 for libcrypto.so.3)
+func GetOpenSSLLibraryVersion(libraryPath string) string {
+	name := libraryPath[strings.LastIndex(libraryPath, "/")+1:]
+	if matches := openSSLLibraryVersion.FindStringSubmatch(name); matches != nil {
+		return matches[1]
+	}
+	return ""
+}
+
+// IsOpenSSLFIPSConfig returns true if the OpenSSL configuration activates the FIPS provider
+func IsOpenSSLFIPSConfig(r io.Reader) bool {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return false
+	}
+	return openSSLFIPSConfig.Match(content)
+}
+
+// GetJavaSecurityProviders returns the names of the security providers configured
+// by the <property>.<n> properties of a java.security file, in order of preference.
+// The property is security.provider for the default providers. The Red Hat builds of OpenJDK
+// also have fips.provider properties for the providers that are used when the system is in FIPS mode.
+func GetJavaSecurityProviders(r io.Reader, property string) []string {
+	providers := make(map[int]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, found := strings.Cut(line, "=")
+		if !found || strings.HasPrefix(line, "#") {
+			continue
+		}
+		index, found := strings.CutPrefix(strings.TrimSpace(key), property+".")
+		if !found {
+			continue
+		}
+		n, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		// the provider name can be followed by its configuration (for example "SunPKCS11 ${java.home}/conf/security/nss.cfg")
+		if fields := strings.Fields(value); len(fields) > 0 {
+			providers[n] = fields[0]
+		}
+	}
+	indexes := make([]int, 0, len(providers))
+	for n := range providers {
+		indexes = append(indexes, n)
+	}
+	sort.Ints(indexes)
+	names := make([]string, 0, len(indexes))
+	for _, n := range indexes {
+		names = append(names, providers[n])
+	}
+	return names
+}
+
+// GetJavaSecurityProperty returns the value of the property of a java.security file (empty if it is not set)
+func GetJavaSecurityProperty(r io.Reader, property string) string {
+	value := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, v, found := strings.Cut(line, "=")
+		if found && !strings.HasPrefix(line, "#") && strings.TrimSpace(key) == property {
+			// the last definition wins
+			value = strings.TrimSpace(v)
+		}
+	}
+	return value
+}
+
+// IsJavaFIPSProvider returns true if the Java security provider is FIPS 140 validated
+func IsJavaFIPSProvider(provider string) bool {
+	return slices.Contains(javaFIPSProviders, provider)
+}
+
+// GetGoFIPSBuildTags returns the FIPS related build tags and experiments of the Go executable
+func GetGoFIPSBuildTags(executable string) []string {
+	bi, err := buildinfo.ReadFile(executable)
+	if err != nil {
+		return nil
+	}
+	tags := []string{}
+	for _, setting := range bi.Settings {
+		var values []string
+		switch setting.Key {
+		case "-tags", "GOEXPERIMENT":
+			values = strings.Split(setting.Value, ",")
+		case "GOFIPS140":
+			// native FIPS 140-3 module of Go 1.24+
+			if setting.Value != "" && setting.Value != "off" {
+				tags = append(tags, "fips140")
+			}
+		}
+		for _, value := range values {
+			if slices.Contains(goFIPSTags, value) && !slices.Contains(tags, value) {
+				tags = append(tags, value)
+			}
+		}
+	}
+	return tags
+}
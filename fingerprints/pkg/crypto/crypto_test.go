@@ -0,0 +1,94 @@
+package crypto
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOpenSSLVersion(t *testing.T) {
+	dir := t.TempDir()
+	library := filepath.Join(dir, "libcrypto.so.3")
+	err := os.WriteFile(library, []byte("\x7fELF\x00\x00OpenSSL 3.0.7 1 Nov 2022\x00OpenSSL 3.0 legacy\x00"), 0644)
+	assert.NoError(t, err)
+	assert.Equal(t, "3.0.7", GetOpenSSLVersion(library))
+
+	// no version string in the library
+	library = filepath.Join(dir, "libssl.so.1.1")
+	err = os.WriteFile(library, []byte("\x7fELF\x00\x00"), 0644)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1", GetOpenSSLVersion(library))
+}
+
+func TestGetOpenSSLLibraryVersion(t *testing.T) {
+	assert.Equal(t, "3", GetOpenSSLLibraryVersion("/usr/lib64/libcrypto.so.3"))
+	assert.Equal(t, "3.0.7", GetOpenSSLLibraryVersion("/usr/lib64/libssl.so.3.0.7"))
+	assert.Equal(t, "1.0.2k", GetOpenSSLLibraryVersion("/usr/lib64/libssl.so.1.0.2k"))
+	assert.Equal(t, "", GetOpenSSLLibraryVersion("/usr/lib64/libcrypto.so"))
+	assert.Equal(t, "", GetOpenSSLLibraryVersion("/usr/lib64/libz.so.1"))
+}
+
+func TestIsOpenSSLFIPSConfig(t *testing.T) {
+	assert.True(t, IsOpenSSLFIPSConfig(strings.NewReader(`
+.include /usr/local/ssl/fipsmodule.cnf
+
+[provider_sect]
+fips = fips_sect
+base = base_sect
+`)))
+	assert.True(t, IsOpenSSLFIPSConfig(strings.NewReader(`
+[provider_sect]
+default = default_sect
+  fips = fips_sect
+`)))
+	// FIPS provider commented out
+	assert.False(t, IsOpenSSLFIPSConfig(strings.NewReader(`
+# .include fipsmodule.cnf
+[provider_sect]
+default = default_sect
+# fips = fips_sect
+`)))
+}
+
+func TestGetJavaSecurityProviders(t *testing.T) {
+	javaSecurity := `
+# security.provider.1=Commented
+security.provider.2=SunRsaSign
+security.provider.1=SUN
+security.provider.10=SunPKCS11 ${java.home}/conf/security/nss.fips.cfg
+security.provider.3 = SunEC
+securerandom.source=file:/dev/random
+fips.provider.1=SunPKCS11 ${java.home}/conf/security/nss.fips.cfg
+`
+	providers := GetJavaSecurityProviders(strings.NewReader(javaSecurity), "security.provider")
+	assert.Equal(t, []string{"SUN", "SunRsaSign", "SunEC", "SunPKCS11"}, providers)
+	providers = GetJavaSecurityProviders(strings.NewReader(javaSecurity), "fips.provider")
+	assert.Equal(t, []string{"SunPKCS11"}, providers)
+}
+
+func TestGetJavaSecurityProperty(t *testing.T) {
+	javaSecurity := `
+#security.useSystemPropertiesFile=false
+security.useSystemPropertiesFile = true
+security.provider.1=SUN
+`
+	assert.Equal(t, "true", GetJavaSecurityProperty(strings.NewReader(javaSecurity), "security.useSystemPropertiesFile"))
+	assert.Equal(t, "", GetJavaSecurityProperty(strings.NewReader(javaSecurity), "securerandom.source"))
+}
+
+func TestIsJavaFIPSProvider(t *testing.T) {
+	assert.True(t, IsJavaFIPSProvider("org.bouncycastle.jcajce.provider.BouncyCastleFipsProvider"))
+	assert.False(t, IsJavaFIPSProvider("SUN"))
+}
+
+func TestGetGoFIPSBuildTags(t *testing.T) {
+	// the test binary is not built with FIPS tags
+	executable, err := os.Executable()
+	assert.NoError(t, err)
+	assert.Empty(t, GetGoFIPSBuildTags(executable))
+
+	assert.Nil(t, GetGoFIPSBuildTags(filepath.Join(t.TempDir(), "missing")))
+}
@@ -0,0 +1,213 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// Agents fingerprints the APM and observability agents loaded by Java, Node.js and Python processes
+type Agents struct{}
+
+func init() {
+	Register(&Agents{})
+}
+
+func (*Agents) Name() string {
+	return "agents"
+}
+
+func (*Agents) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	cwd := process.Cwd
+	javaToolOptions := process.Env("JAVA_TOOL_OPTIONS")
+	nodeOptions := process.Env("NODE_OPTIONS")
+	pythonPath := process.Env("PYTHONPATH")
+	commandLine := process.CommandLine
+
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+	agents := config.Fingerprints.Agents
+
+	// each agent is written as a separate result with its own evidence
+	write := func(name string, version string, confidence Confidence, evidence Evidence) error {
+		return ctx.Write("agents-fingerprints", map[string]string{name: version}, confidence, evidence)
+	}
+
+	// Java agents
+	javaArgs := append(strings.Fields(javaToolOptions), commandLine...)
+	for _, arg := range javaArgs {
+		if agentJar, found := strings.CutPrefix(arg, "-javaagent:"); found {
+			// remove the agent options
+			agentJar, _, _ = strings.Cut(agentJar, "=")
+			if !filepath.IsAbs(agentJar) {
+				agentJar = filepath.Join(cwd, agentJar)
+			}
+			if name, version, found := getJavaAgent(ctx.Root, agents, agentJar); found {
+				evidence := Evidence{Type: EvidenceManifestEntry, Path: agentJar, Detail: "Premain-Class"}
+				if err := write(name, version, ConfidenceHigh, evidence); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Node.js agents
+	nodeArgs := strings.Fields(nodeOptions)
+	if len(commandLine) > 0 && strings.Contains(filepath.Base(commandLine[0]), "node") {
+		nodeArgs = append(nodeArgs, commandLine[1:]...)
+	}
+	for _, module := range getNodePreloadedModules(nodeArgs) {
+		if name, packageDir, found := getNodeAgent(agents, cwd, module); found {
+			packageJSON := filepath.Join(packageDir, "package.json")
+			evidence := Evidence{Type: EvidenceFile, Path: packageJSON}
+			if err := write(name, getNodePackageVersion(ctx.Root, packageJSON), ConfidenceHigh, evidence); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Python agents
+	for _, agent := range agents {
+		if agent.PythonBootstrapPath == "" {
+			continue
+		}
+		if name, version, evidence, found := getPythonAgent(ctx.Root, agent, pythonPath, commandLine); found {
+			// the wrapper in the command line does not prove that the agent is installed
+			confidence := ConfidenceHigh
+			if evidence.Type == EvidenceCommandLine {
+				confidence = ConfidenceMedium
+			}
+			if err := write(name, version, confidence, evidence); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getJavaAgent identifies the Java agent from the Premain-Class entry of its jar manifest
+// and returns its name and version
+func getJavaAgent(root string, agents []utils.Agent, agentJar string) (string, string, bool) {
+	manifest, err := utils.GetJarManifest(root, agentJar)
+	if err != nil {
+		log.Printf("Unable to read manifest entries from Java agent %s: %s\n", agentJar, err)
+		return "", "", false
+	}
+	premainClass := manifest.Main.Get("Premain-Class")
+	for _, agent := range agents {
+		if agent.JavaPremainClassPrefix != "" && strings.HasPrefix(premainClass, agent.JavaPremainClassPrefix) {
+			return agent.AgentName, manifest.Main.Get("Implementation-Version"), true
+		}
+	}
+	return "", "", false
+}
+
+// getNodePreloadedModules returns the modules that are preloaded by the Node.js options
+// (`--require`, `-r` or `--import`)
+func getNodePreloadedModules(args []string) []string {
+	modules := []string{}
+	for i, arg := range args {
+		switch {
+		case arg == "--require" || arg == "-r" || arg == "--import":
+			if i+1 < len(args) {
+				modules = append(modules, args[i+1])
+			}
+		case strings.HasPrefix(arg, "--require="):
+			modules = append(modules, strings.TrimPrefix(arg, "--require="))
+		case strings.HasPrefix(arg, "--import="):
+			modules = append(modules, strings.TrimPrefix(arg, "--import="))
+		}
+	}
+	return modules
+}
+
+// getNodeAgent identifies the Node.js agent from a preloaded module and returns its name and package directory.
+//
+// The preloaded module can be a package name (`dd-trace/init`) or a path to a file inside a package
+// (`/app/node_modules/newrelic/index.js`).
+func getNodeAgent(agents []utils.Agent, cwd string, module string) (string, string, bool) {
+	for _, agent := range agents {
+		for _, nodeModule := range agent.NodeModules {
+			var packageDir string
+			if module == nodeModule || strings.HasPrefix(module, nodeModule+"/") {
+				packageDir = filepath.Join(cwd, "node_modules", nodeModule)
+			} else if before, _, found := strings.Cut(module, "node_modules/"+nodeModule); found {
+				packageDir = before + "node_modules/" + nodeModule
+				if !filepath.IsAbs(packageDir) {
+					packageDir = filepath.Join(cwd, packageDir)
+				}
+			} else {
+				continue
+			}
+			return agent.AgentName, packageDir, true
+		}
+	}
+	return "", "", false
+}
+
+func getNodePackageVersion(root string, packageJSONPath string) string {
+	content, err := utils.ReadFile(root, packageJSONPath)
+	if err != nil {
+		return ""
+	}
+	var packageJSON struct {
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(content, &packageJSON); err != nil {
+		return ""
+	}
+	return packageJSON.Version
+}
+
+// getPythonAgent checks whether the Python agent is bootstrapped by a sitecustomize module
+// in the PYTHONPATH (set by wrappers such as `opentelemetry-instrument`) or if its wrapper is in the command line.
+// It returns the name of the agent, its version (read from the distribution metadata in site-packages)
+// and the evidence of the agent.
+func getPythonAgent(root string, agent utils.Agent, pythonPath string, commandLine []string) (string, string, Evidence, bool) {
+	for _, dir := range strings.Split(pythonPath, string(os.PathListSeparator)) {
+		if !strings.HasSuffix(strings.TrimSuffix(dir, "/"), agent.PythonBootstrapPath) {
+			continue
+		}
+		sitecustomize := filepath.Join(dir, "sitecustomize.py")
+		if _, err := utils.Stat(root, sitecustomize); err != nil {
+			continue
+		}
+		sitePackages, _, _ := strings.Cut(dir, agent.PythonBootstrapPath)
+		version := getPythonDistributionVersion(root, sitePackages, agent.PythonDistribution)
+		return agent.AgentName, version, Evidence{Type: EvidenceFile, Path: sitecustomize}, true
+	}
+	if agent.PythonWrapper == "" {
+		return "", "", Evidence{}, false
+	}
+	for _, arg := range commandLine {
+		if filepath.Base(arg) == agent.PythonWrapper {
+			return agent.AgentName, "", Evidence{Type: EvidenceCommandLine, Detail: arg}, true
+		}
+	}
+	return "", "", Evidence{}, false
+}
+
+var distInfoVersion = regexp.MustCompile(`-([^-]+)\.dist-info$`)
+
+func getPythonDistributionVersion(root string, sitePackages string, distribution string) string {
+	if sitePackages == "" || distribution == "" {
+		return ""
+	}
+	for _, match := range utils.Glob(root, filepath.Join(sitePackages, distribution+"-*.dist-info")) {
+		if version := distInfoVersion.FindStringSubmatch(filepath.Base(match)); version != nil {
+			return version[1]
+		}
+	}
+	return ""
+}
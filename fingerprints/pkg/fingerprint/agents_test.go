@@ -0,0 +1,122 @@
+package fingerprint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"fingerprints/pkg/utils"
+)
+
+var agentsTestConfig = []utils.Agent{
+	{AgentName: "OpenTelemetry", JavaPremainClassPrefix: "io.opentelemetry.javaagent.", NodeModules: []string{"@opentelemetry/auto-instrumentations-node"},
+		PythonBootstrapPath: "opentelemetry/instrumentation/auto_instrumentation", PythonDistribution: "opentelemetry_instrumentation", PythonWrapper: "opentelemetry-instrument"},
+	{AgentName: "Datadog", JavaPremainClassPrefix: "datadog.trace.bootstrap.", NodeModules: []string{"dd-trace"}},
+}
+
+func TestGetJavaAgent(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/otel/opentelemetry-javaagent.jar", string(createJar(t, map[string][]byte{
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\r\nPremain-Class: io.opentelemetry.javaagent.OpenTelemetryAgent\r\nImplementation-Version: 2.4.0\r\n"),
+	})))
+	writeRootFile(t, root, "/agents/custom.jar", string(createJar(t, map[string][]byte{
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\r\nPremain-Class: com.acme.Agent\r\n"),
+	})))
+
+	for _, test := range []struct {
+		jar     string
+		name    string
+		version string
+		found   bool
+	}{
+		{jar: "/otel/opentelemetry-javaagent.jar", name: "OpenTelemetry", version: "2.4.0", found: true},
+		// the agent is identified by its Premain-Class, not by the name of its jar
+		{jar: "/agents/custom.jar"},
+		{jar: "/agents/missing.jar"},
+	} {
+		name, version, found := getJavaAgent(root, agentsTestConfig, test.jar)
+		assert.Equal(t, test.found, found, test.jar)
+		assert.Equal(t, test.name, name, test.jar)
+		assert.Equal(t, test.version, version, test.jar)
+	}
+}
+
+func TestGetNodePreloadedModules(t *testing.T) {
+	for _, test := range []struct {
+		args    []string
+		modules []string
+	}{
+		{args: []string{"--require", "dd-trace/init", "server.js"}, modules: []string{"dd-trace/init"}},
+		{args: []string{"-r", "newrelic", "--import=@opentelemetry/auto-instrumentations-node/register"}, modules: []string{"newrelic", "@opentelemetry/auto-instrumentations-node/register"}},
+		{args: []string{"--require=./tracing.js", "--import", "./instrument.mjs"}, modules: []string{"./tracing.js", "./instrument.mjs"}},
+		{args: []string{"--max-old-space-size=512", "server.js"}, modules: []string{}},
+		// the option has no value
+		{args: []string{"--require"}, modules: []string{}},
+	} {
+		assert.Equal(t, test.modules, getNodePreloadedModules(test.args), test.args)
+	}
+}
+
+func TestGetNodeAgent(t *testing.T) {
+	for _, test := range []struct {
+		module     string
+		name       string
+		packageDir string
+		found      bool
+	}{
+		{module: "dd-trace", name: "Datadog", packageDir: "/app/node_modules/dd-trace", found: true},
+		{module: "dd-trace/init", name: "Datadog", packageDir: "/app/node_modules/dd-trace", found: true},
+		{module: "/opt/node_modules/dd-trace/init.js", name: "Datadog", packageDir: "/opt/node_modules/dd-trace", found: true},
+		{module: "./node_modules/@opentelemetry/auto-instrumentations-node/register", name: "OpenTelemetry",
+			packageDir: "/app/node_modules/@opentelemetry/auto-instrumentations-node", found: true},
+		// a module whose name starts with the name of an agent package
+		{module: "dd-trace-extra"},
+		{module: "./tracing.js"},
+	} {
+		name, packageDir, found := getNodeAgent(agentsTestConfig, "/app", test.module)
+		assert.Equal(t, test.found, found, test.module)
+		assert.Equal(t, test.name, name, test.module)
+		assert.Equal(t, test.packageDir, packageDir, test.module)
+	}
+}
+
+func TestGetPythonAgent(t *testing.T) {
+	root := t.TempDir()
+	sitePackages := "/opt/app-root/lib/python3.11/site-packages"
+	writeRootFile(t, root, sitePackages+"/opentelemetry/instrumentation/auto_instrumentation/sitecustomize.py", "")
+	writeRootFile(t, root, sitePackages+"/opentelemetry_instrumentation-0.46b0.dist-info/METADATA", "")
+
+	for _, test := range []struct {
+		pythonPath  string
+		commandLine []string
+		version     string
+		evidence    Evidence
+		found       bool
+	}{
+		{
+			pythonPath:  "/app:" + sitePackages + "/opentelemetry/instrumentation/auto_instrumentation",
+			commandLine: []string{"python", "app.py"},
+			version:     "0.46b0",
+			evidence:    Evidence{Type: EvidenceFile, Path: sitePackages + "/opentelemetry/instrumentation/auto_instrumentation/sitecustomize.py"},
+			found:       true,
+		},
+		{
+			commandLine: []string{"/usr/local/bin/opentelemetry-instrument", "python", "app.py"},
+			evidence:    Evidence{Type: EvidenceCommandLine, Detail: "/usr/local/bin/opentelemetry-instrument"},
+			found:       true,
+		},
+		// the bootstrap directory has no sitecustomize module
+		{
+			pythonPath:  "/usr/lib/python3.11/site-packages/opentelemetry/instrumentation/auto_instrumentation",
+			commandLine: []string{"python", "app.py"},
+		},
+	} {
+		name, version, evidence, found := getPythonAgent(root, agentsTestConfig[0], test.pythonPath, test.commandLine)
+		assert.Equal(t, test.found, found, test.commandLine)
+		if test.found {
+			assert.Equal(t, "OpenTelemetry", name)
+		}
+		assert.Equal(t, test.version, version, test.commandLine)
+		assert.Equal(t, test.evidence, evidence, test.commandLine)
+	}
+}
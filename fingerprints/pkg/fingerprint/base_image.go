@@ -0,0 +1,166 @@
+package fingerprint
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+const (
+	buildInfoDir = "/root/buildinfo"
+)
+
+// labels of the Dockerfile (LABEL key="value" ...)
+var dockerfileLabel = regexp.MustCompile(`([\w.\-]+)=("(?:[^"\\]|\\.)*"|\S+)`)
+
+// dockerfileImage is an image described by a /root/buildinfo/Dockerfile-* file
+type dockerfileImage struct {
+	dockerfile string
+	component  string
+	name       string
+	version    string
+	release    string
+}
+
+// contentManifest is a /root/buildinfo/content_manifests/*.json file
+type contentManifest struct {
+	Metadata struct {
+		ImageLayerIndex int `json:"image_layer_index"`
+	} `json:"metadata"`
+	ContentSets []string `json:"content_sets"`
+}
+
+// BaseImage fingerprints the Red Hat base image of the container from the build information in /root/buildinfo
+type BaseImage struct{}
+
+func init() {
+	Register(&BaseImage{})
+}
+
+func (*BaseImage) Name() string {
+	return "base-image"
+}
+
+func (*BaseImage) Fingerprint(ctx *Context) error {
+	images := readDockerfiles(ctx.Root, filepath.Join(buildInfoDir, "Dockerfile-*"))
+	if len(images) == 0 {
+		return nil
+	}
+
+	// the base image is the image of the lowest layer that has a content manifest
+	// (content manifests are named after the component, version and release of the image)
+	baseImage := images[0]
+	baseManifest := ""
+	baseLayerIndex := math.MaxInt
+	contentSets := []string{}
+	for _, manifestPath := range utils.Glob(ctx.Root, filepath.Join(buildInfoDir, "content_manifests", "*.json")) {
+		manifest, err := readContentManifest(ctx.Root, manifestPath)
+		if err != nil {
+			log.Printf("Unable to read content manifest %s: %s\n", manifestPath, err)
+			continue
+		}
+		for _, contentSet := range manifest.ContentSets {
+			if !slices.Contains(contentSets, contentSet) {
+				contentSets = append(contentSets, contentSet)
+			}
+		}
+		manifestName := strings.TrimSuffix(filepath.Base(manifestPath), ".json")
+		for _, image := range images {
+			if manifestName == image.component+"-"+image.version+"-"+image.release && manifest.Metadata.ImageLayerIndex < baseLayerIndex {
+				baseImage = image
+				baseManifest = manifestPath
+				baseLayerIndex = manifest.Metadata.ImageLayerIndex
+			}
+		}
+	}
+	slices.Sort(contentSets)
+
+	entries := make(map[string]string)
+	entries["base-image-name"] = baseImage.name
+	entries["base-image-component"] = baseImage.component
+	entries["base-image-version"] = baseImage.version
+	if baseImage.release != "" {
+		entries["base-image-version"] = baseImage.version + "-" + baseImage.release
+	}
+	if len(contentSets) > 0 {
+		entries["base-image-content-sets"] = strings.Join(contentSets, ",")
+	}
+
+	evidence := []Evidence{{Type: EvidenceFile, Path: baseImage.dockerfile, Detail: "LABEL name"}}
+	// without content manifest, the base image is guessed from the first Dockerfile
+	confidence := ConfidenceMedium
+	if baseManifest != "" {
+		evidence = append(evidence, Evidence{Type: EvidenceFile, Path: baseManifest, Detail: "image_layer_index"})
+		confidence = ConfidenceHigh
+	}
+	return ctx.Write("base-image", entries, confidence, evidence...)
+}
+
+// readDockerfiles returns the images described by the Dockerfiles, sorted by their file names
+func readDockerfiles(root string, pattern string) []dockerfileImage {
+	images := []dockerfileImage{}
+	for _, dockerfile := range utils.Glob(root, pattern) {
+		labels, err := readDockerfileLabels(root, dockerfile)
+		if err != nil || labels["name"] == "" {
+			continue
+		}
+		images = append(images, dockerfileImage{
+			dockerfile: dockerfile,
+			component:  labels["com.redhat.component"],
+			name:       labels["name"],
+			version:    labels["version"],
+			release:    labels["release"],
+		})
+	}
+	return images
+}
+
+// readDockerfileLabels returns the labels set by the LABEL instructions of the Dockerfile
+func readDockerfileLabels(root string, dockerfile string) (map[string]string, error) {
+	file, err := utils.Open(root, dockerfile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	labels := make(map[string]string)
+	instruction := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// join the lines continued by a trailing backslash
+		if continued, found := strings.CutSuffix(line, "\\"); found {
+			instruction += continued + " "
+			continue
+		}
+		instruction += line
+		if labelArgs, found := strings.CutPrefix(instruction, "LABEL "); found {
+			for _, match := range dockerfileLabel.FindAllStringSubmatch(labelArgs, -1) {
+				value := match[2]
+				if strings.HasPrefix(value, "\"") {
+					value = strings.ReplaceAll(strings.Trim(value, "\""), "\\\"", "\"")
+				}
+				labels[match[1]] = value
+			}
+		}
+		instruction = ""
+	}
+	return labels, scanner.Err()
+}
+
+func readContentManifest(root string, path string) (contentManifest, error) {
+	var manifest contentManifest
+	content, err := utils.ReadFile(root, path)
+	if err != nil {
+		return manifest, err
+	}
+	err = json.Unmarshal(content, &manifest)
+	return manifest, err
+}
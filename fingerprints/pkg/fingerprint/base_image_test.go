@@ -0,0 +1,131 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDockerfileLabels(t *testing.T) {
+	for _, test := range []struct {
+		dockerfile string
+		labels     map[string]string
+	}{
+		{
+			dockerfile: "FROM scratch\nLABEL name=\"ubi9\" version=\"9.4\"\n",
+			labels:     map[string]string{"name": "ubi9", "version": "9.4"},
+		},
+		{
+			dockerfile: "LABEL com.redhat.component=ubi9-minimal-container \\\n      name=\"ubi9/ubi-minimal\" \\\n      release=1194\n",
+			labels:     map[string]string{"com.redhat.component": "ubi9-minimal-container", "name": "ubi9/ubi-minimal", "release": "1194"},
+		},
+		{
+			dockerfile: "LABEL summary=\"Provides the \\\"latest\\\" release\" io.k8s.display-name=\"Red Hat Universal Base Image 9\"\n",
+			labels:     map[string]string{"summary": "Provides the \"latest\" release", "io.k8s.display-name": "Red Hat Universal Base Image 9"},
+		},
+		{
+			// the later labels replace the earlier ones
+			dockerfile: "LABEL name=\"ubi9\"\nRUN microdnf install -y java-17 && echo name=other\nLABEL name=\"ubi9/openjdk-17\"\n",
+			labels:     map[string]string{"name": "ubi9/openjdk-17"},
+		},
+		{
+			dockerfile: "FROM registry.access.redhat.com/ubi9\nENV name=ubi9\n",
+			labels:     map[string]string{},
+		},
+	} {
+		root := t.TempDir()
+		writeRootFile(t, root, "/root/buildinfo/Dockerfile-ubi9", test.dockerfile)
+		labels, err := readDockerfileLabels(root, "/root/buildinfo/Dockerfile-ubi9")
+		assert.NoError(t, err)
+		assert.Equal(t, test.labels, labels, test.dockerfile)
+	}
+}
+
+func TestReadDockerfiles(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/root/buildinfo/Dockerfile-ubi9-minimal-container-9.4-1194", "LABEL com.redhat.component=\"ubi9-minimal-container\" name=\"ubi9/ubi-minimal\" version=\"9.4\" release=\"1194\"\n")
+	writeRootFile(t, root, "/root/buildinfo/Dockerfile-openjdk-17-runtime-ubi9-container-1.18-4", "LABEL com.redhat.component=\"openjdk-17-runtime-ubi9-container\" name=\"ubi9/openjdk-17-runtime\" version=\"1.18\" release=\"4\"\n")
+	// Dockerfiles without name are ignored
+	writeRootFile(t, root, "/root/buildinfo/Dockerfile-acme", "LABEL version=\"1.0\"\n")
+
+	// the images are sorted by the names of their Dockerfiles
+	assert.Equal(t, []dockerfileImage{
+		{dockerfile: "/root/buildinfo/Dockerfile-openjdk-17-runtime-ubi9-container-1.18-4", component: "openjdk-17-runtime-ubi9-container", name: "ubi9/openjdk-17-runtime", version: "1.18", release: "4"},
+		{dockerfile: "/root/buildinfo/Dockerfile-ubi9-minimal-container-9.4-1194", component: "ubi9-minimal-container", name: "ubi9/ubi-minimal", version: "9.4", release: "1194"},
+	}, readDockerfiles(root, "/root/buildinfo/Dockerfile-*"))
+	assert.Empty(t, readDockerfiles(t.TempDir(), "/root/buildinfo/Dockerfile-*"))
+}
+
+func TestReadContentManifest(t *testing.T) {
+	root := t.TempDir()
+	path := "/root/buildinfo/content_manifests/ubi9-minimal-container-9.4-1194.json"
+	writeRootFile(t, root, path, `{"metadata": {"icm_version": 1, "image_layer_index": 2}, "content_sets": ["rhel-9-for-x86_64-baseos-rpms", "rhel-9-for-x86_64-appstream-rpms"]}`)
+	manifest, err := readContentManifest(root, path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, manifest.Metadata.ImageLayerIndex)
+	assert.Equal(t, []string{"rhel-9-for-x86_64-baseos-rpms", "rhel-9-for-x86_64-appstream-rpms"}, manifest.ContentSets)
+
+	writeRootFile(t, root, path, "{")
+	_, err = readContentManifest(root, path)
+	assert.Error(t, err)
+}
+
+func TestBaseImage(t *testing.T) {
+	dockerfiles := map[string]string{
+		"/root/buildinfo/Dockerfile-openjdk-17-runtime-ubi9-container-1.18-4": "LABEL com.redhat.component=\"openjdk-17-runtime-ubi9-container\" name=\"ubi9/openjdk-17-runtime\" version=\"1.18\" release=\"4\"\n",
+		"/root/buildinfo/Dockerfile-ubi9-minimal-container-9.4-1194":          "LABEL com.redhat.component=\"ubi9-minimal-container\" name=\"ubi9/ubi-minimal\" version=\"9.4\" release=\"1194\"\n",
+		// Dockerfiles without name are ignored
+		"/root/buildinfo/Dockerfile-acme": "LABEL version=\"1.0\"\n",
+	}
+	for _, test := range []struct {
+		name       string
+		manifests  map[string]string
+		values     map[string]string
+		confidence Confidence
+	}{
+		{
+			// the base image is the image of the lowest layer
+			name: "content manifests",
+			manifests: map[string]string{
+				"/root/buildinfo/content_manifests/openjdk-17-runtime-ubi9-container-1.18-4.json": `{"metadata": {"image_layer_index": 1}, "content_sets": ["rhel-9-for-x86_64-appstream-rpms"]}`,
+				"/root/buildinfo/content_manifests/ubi9-minimal-container-9.4-1194.json":          `{"metadata": {"image_layer_index": 0}, "content_sets": ["rhel-9-for-x86_64-baseos-rpms"]}`,
+			},
+			values: map[string]string{"base-image-name": "ubi9/ubi-minimal", "base-image-component": "ubi9-minimal-container", "base-image-version": "9.4-1194",
+				"base-image-content-sets": "rhel-9-for-x86_64-appstream-rpms,rhel-9-for-x86_64-baseos-rpms"},
+			confidence: ConfidenceHigh,
+		},
+		{
+			// the base image is guessed from the first Dockerfile
+			name:      "no content manifests",
+			manifests: map[string]string{},
+			values: map[string]string{"base-image-name": "ubi9/openjdk-17-runtime", "base-image-component": "openjdk-17-runtime-ubi9-container",
+				"base-image-version": "1.18-4"},
+			confidence: ConfidenceMedium,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, content := range dockerfiles {
+				writeRootFile(t, root, path, content)
+			}
+			for path, content := range test.manifests {
+				writeRootFile(t, root, path, content)
+			}
+			outputDir := t.TempDir()
+
+			assert.NoError(t, Run("base-image", []string{"--root", root, outputDir}))
+			files, _ := filepath.Glob(filepath.Join(outputDir, "base-image.base-image.*.json"))
+			if assert.Len(t, files, 1) {
+				content, err := os.ReadFile(files[0])
+				assert.NoError(t, err)
+				var result Result
+				assert.NoError(t, json.Unmarshal(content, &result))
+				assert.Equal(t, test.values, result.Values)
+				assert.Equal(t, test.confidence, result.Confidence)
+			}
+		})
+	}
+}
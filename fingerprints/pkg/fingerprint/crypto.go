@@ -0,0 +1,250 @@
+package fingerprint
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fingerprints/pkg/crypto"
+	"fingerprints/pkg/utils"
+)
+
+// Directories of the shared libraries
+var libraryDirs = []string{
+	"/usr/lib64",
+	"/lib64",
+	"/usr/lib",
+	"/lib",
+	"/usr/lib/*-linux-gnu",
+	"/lib/*-linux-gnu",
+	"/usr/local/lib64",
+	"/usr/local/lib",
+	"/usr/local/ssl/lib",
+}
+
+// Locations of the OpenSSL configuration
+var openSSLConfigs = []string{
+	"/etc/pki/tls/openssl.cnf",
+	"/etc/ssl/openssl.cnf",
+	"/usr/lib/ssl/openssl.cnf",
+	"/usr/local/ssl/openssl.cnf",
+}
+
+// Locations of the system-wide crypto policy of RHEL and Fedora
+var cryptoPolicies = []string{
+	"/etc/crypto-policies/state/current",
+	"/etc/crypto-policies/config",
+}
+
+// Flag of the kernel set when the host is in FIPS mode
+const kernelFIPSFlag = "/proc/sys/crypto/fips_enabled"
+
+// Crypto fingerprints the crypto libraries of the container and assesses whether the process can operate in FIPS mode
+type Crypto struct{}
+
+func init() {
+	Register(&Crypto{})
+}
+
+func (*Crypto) Name() string {
+	return "crypto"
+}
+
+func (*Crypto) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	executable := resolveExecutable(ctx.Root, process.Cwd, process.Executable(), process.Env("PATH"))
+	javaHomeEnvVar := process.Env("JAVA_HOME")
+
+	entries := make(map[string]string)
+	// the evidence of the FIPS assessment reported in the values and the sources of the values
+	fipsEvidence := []string{}
+	sources := []Evidence{}
+
+	// OpenSSL
+	openSSLFIPS := false
+	if library := findLibrary(ctx.Root, "libcrypto.so.*", "libssl.so.*"); library != "" {
+		entries["openssl-library"] = library
+		sources = append(sources, Evidence{Type: EvidenceFile, Path: library})
+		if version := getOpenSSLVersion(ctx.Root, library); version != "" {
+			entries["openssl-version"] = version
+		}
+	}
+	if fipsProvider := findLibrary(ctx.Root, filepath.Join("ossl-modules", "fips.so")); fipsProvider != "" {
+		entries["openssl-fips-provider"] = fipsProvider
+		sources = append(sources, Evidence{Type: EvidenceFile, Path: fipsProvider})
+		fipsEvidence = append(fipsEvidence, "openssl-fips-provider")
+		openSSLFIPS = true
+	}
+	for _, config := range openSSLConfigs {
+		file, err := utils.Open(ctx.Root, config)
+		if err != nil {
+			continue
+		}
+		fipsConfig := crypto.IsOpenSSLFIPSConfig(file)
+		file.Close()
+		if fipsConfig {
+			entries["openssl-fips-config"] = config
+			sources = append(sources, Evidence{Type: EvidenceFile, Path: config})
+			fipsEvidence = append(fipsEvidence, "openssl-fips-config")
+		}
+		break
+	}
+	cryptoPolicyFIPS := false
+	for _, policyPath := range cryptoPolicies {
+		content, err := utils.ReadFile(ctx.Root, policyPath)
+		if err != nil {
+			continue
+		}
+		policy, _, _ := strings.Cut(strings.TrimSpace(string(content)), "\n")
+		entries["crypto-policy"] = policy
+		sources = append(sources, Evidence{Type: EvidenceFile, Path: policyPath})
+		if strings.HasPrefix(policy, "FIPS") {
+			fipsEvidence = append(fipsEvidence, "crypto-policy-fips")
+			cryptoPolicyFIPS = true
+		}
+		break
+	}
+
+	// the FIPS capability depends on the crypto module used by the runtime of the process
+	fipsCapable := openSSLFIPS
+	if javaHomeDir := getJavaHome(ctx.Root, executable, javaHomeEnvVar); javaHomeDir != "" {
+		javaSecurity := getJavaSecurity(ctx.Root, javaHomeDir)
+		if javaSecurity.path != "" {
+			sources = append(sources, Evidence{Type: EvidenceFile, Path: javaSecurity.path})
+		}
+		if len(javaSecurity.providers) > 0 {
+			entries["java-security-providers"] = strings.Join(javaSecurity.providers, ",")
+		}
+		javaFIPS := slices.ContainsFunc(javaSecurity.providers, crypto.IsJavaFIPSProvider)
+		if javaFIPS {
+			fipsEvidence = append(fipsEvidence, "java-fips-provider")
+		}
+		// the Red Hat builds of OpenJDK always have fips.provider properties:
+		// they are only used when the system is in FIPS mode
+		systemFIPS := openSSLFIPS || cryptoPolicyFIPS || (javaSecurity.useSystemPropertiesFile && isKernelFIPS(ctx.Root))
+		javaSystemFIPS := len(javaSecurity.fipsProviders) > 0 && systemFIPS
+		if javaSystemFIPS {
+			fipsEvidence = append(fipsEvidence, "java-system-fips-providers")
+		}
+		fipsCapable = javaFIPS || javaSystemFIPS
+	} else if tags := getGoFIPSBuildTags(ctx.Root, executable); tags != nil {
+		if len(tags) > 0 {
+			entries["go-fips-build-tags"] = strings.Join(tags, ",")
+			sources = append(sources, Evidence{Type: EvidenceBuildInfo, Path: executable, Detail: "-tags"})
+		}
+		fipsCapable = false
+		for _, tag := range tags {
+			fipsEvidence = append(fipsEvidence, "go-"+tag)
+			switch tag {
+			case "boringcrypto", "fips140":
+				// the FIPS module is embedded in the executable
+				fipsCapable = true
+			case "strictfipsruntime", "opensslcrypto":
+				// the executable uses the FIPS provider of OpenSSL
+				fipsCapable = fipsCapable || openSSLFIPS
+			}
+		}
+	}
+
+	entries["fips-capable"] = strconv.FormatBool(fipsCapable)
+	if len(fipsEvidence) > 0 {
+		entries["fips-evidence"] = strings.Join(fipsEvidence, ",")
+	}
+	// the FIPS assessment is derived from heuristics on the installed crypto modules
+	return ctx.Write("crypto", entries, ConfidenceMedium, sources...)
+}
+
+// resolveExecutable returns the path of the executable of the process
+func resolveExecutable(root string, cwd string, executable string, pathEnvVar string) string {
+	switch {
+	case filepath.IsAbs(executable):
+		return executable
+	case strings.Contains(executable, "/"):
+		return filepath.Join(cwd, executable)
+	}
+	if path, err := utils.FindExecutableInPath(root, executable, pathEnvVar); err == nil {
+		return path
+	}
+	return executable
+}
+
+// findLibrary returns the first library matching one of the patterns in the library directories
+func findLibrary(root string, patterns ...string) string {
+	for _, pattern := range patterns {
+		for _, dir := range libraryDirs {
+			if matches := utils.Glob(root, filepath.Join(dir, pattern)); len(matches) > 0 {
+				return matches[0]
+			}
+		}
+	}
+	return ""
+}
+
+// javaSecurity is the security configuration of a Java runtime
+type javaSecurity struct {
+	// path of the java.security file
+	path string
+	// default security providers
+	providers []string
+	// providers used when the system is in FIPS mode (Red Hat builds of OpenJDK)
+	fipsProviders []string
+	// set if the runtime follows the FIPS mode of the system (Red Hat builds of OpenJDK)
+	useSystemPropertiesFile bool
+}
+
+// getJavaSecurity returns the security configuration of the java.security file of the Java runtime
+func getJavaSecurity(root string, javaHomeDir string) javaSecurity {
+	paths := []string{
+		// Java 9+
+		filepath.Join(javaHomeDir, "conf", "security", "java.security"),
+		// Java 8 JDK & JRE
+		filepath.Join(javaHomeDir, "jre", "lib", "security", "java.security"),
+		filepath.Join(javaHomeDir, "lib", "security", "java.security"),
+	}
+	for _, path := range paths {
+		content, err := utils.ReadFile(root, path)
+		if err != nil {
+			continue
+		}
+		return javaSecurity{
+			path:                    path,
+			providers:               crypto.GetJavaSecurityProviders(strings.NewReader(string(content)), "security.provider"),
+			fipsProviders:           crypto.GetJavaSecurityProviders(strings.NewReader(string(content)), "fips.provider"),
+			useSystemPropertiesFile: crypto.GetJavaSecurityProperty(strings.NewReader(string(content)), "security.useSystemPropertiesFile") == "true",
+		}
+	}
+	return javaSecurity{}
+}
+
+// isKernelFIPS returns true if the kernel of the host running the container is in FIPS mode.
+// It is unknown (false) for an unpacked root filesystem.
+func isKernelFIPS(root string) bool {
+	if root != "" {
+		return false
+	}
+	content, err := utils.ReadFile(root, kernelFIPSFlag)
+	return err == nil && strings.TrimSpace(string(content)) == "1"
+}
+
+// getOpenSSLVersion returns the version of an OpenSSL library of the root filesystem
+func getOpenSSLVersion(root string, library string) string {
+	libraryPath, err := utils.ResolvePath(root, library)
+	if err != nil {
+		return ""
+	}
+	return crypto.GetOpenSSLVersion(libraryPath)
+}
+
+// getGoFIPSBuildTags returns the FIPS build tags of a Go executable of the root filesystem
+// (or nil if it is not a Go executable)
+func getGoFIPSBuildTags(root string, executable string) []string {
+	executablePath, err := utils.ResolvePath(root, executable)
+	if err != nil {
+		return nil
+	}
+	return crypto.GetGoFIPSBuildTags(executablePath)
+}
@@ -0,0 +1,80 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// java.security of the Red Hat builds of OpenJDK
+const redHatJavaSecurity = `
+security.provider.1=SUN
+security.provider.2=SunRsaSign
+fips.provider.1=SunPKCS11 ${java.home}/conf/security/nss.fips.cfg
+fips.provider.2=SUN
+security.useSystemPropertiesFile=true
+`
+
+// readCryptoValues returns the values of the result written by the crypto fingerprint to the output directory
+func readCryptoValues(t *testing.T, outputDir string) map[string]string {
+	files, _ := filepath.Glob(filepath.Join(outputDir, "crypto.crypto.*.json"))
+	if !assert.Len(t, files, 1) {
+		return nil
+	}
+	content, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	var result Result
+	assert.NoError(t, json.Unmarshal(content, &result))
+	return result.Values
+}
+
+func TestCryptoJavaFIPS(t *testing.T) {
+	for _, test := range []struct {
+		name         string
+		files        map[string]string
+		fipsCapable  string
+		fipsEvidence string
+	}{
+		{
+			// the fips.provider properties are not used when the system is not in FIPS mode
+			name:        "system fips providers without system fips mode",
+			files:       map[string]string{"/usr/lib/jvm/jre/conf/security/java.security": redHatJavaSecurity},
+			fipsCapable: "false",
+		},
+		{
+			name: "system fips providers with a fips crypto policy",
+			files: map[string]string{
+				"/usr/lib/jvm/jre/conf/security/java.security": redHatJavaSecurity,
+				"/etc/crypto-policies/state/current":           "FIPS\n",
+			},
+			fipsCapable:  "true",
+			fipsEvidence: "crypto-policy-fips,java-system-fips-providers",
+		},
+		{
+			name: "fips validated provider",
+			files: map[string]string{
+				"/usr/lib/jvm/jre/conf/security/java.security": "security.provider.1=org.bouncycastle.jcajce.provider.BouncyCastleFipsProvider\n",
+			},
+			fipsCapable:  "true",
+			fipsEvidence: "java-fips-provider",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, content := range test.files {
+				writeRootFile(t, root, path, content)
+			}
+			writeRootFile(t, root, "/usr/lib/jvm/jre/bin/java", "")
+			outputDir := t.TempDir()
+			writeProcess(t, outputDir, "/", map[string]string{"JAVA_HOME": "/usr/lib/jvm/jre"}, "/usr/lib/jvm/jre/bin/java", "-jar", "app.jar")
+
+			assert.NoError(t, Run("crypto", []string{"--root", root, outputDir}))
+			values := readCryptoValues(t, outputDir)
+			assert.Equal(t, test.fipsCapable, values["fips-capable"])
+			assert.Equal(t, test.fipsEvidence, values["fips-evidence"])
+		})
+	}
+}
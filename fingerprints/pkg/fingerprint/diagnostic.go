@@ -0,0 +1,88 @@
+package fingerprint
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fingerprints/pkg/utils"
+)
+
+// KindDiagnostic is the kind of the result that describes how a fingerprint ran.
+// Its values are not runtime information: they tell apart a fingerprint that detected nothing from a fingerprint that failed.
+const KindDiagnostic = "diagnostic"
+
+// Status of a fingerprint in its diagnostic
+const (
+	// the fingerprint ran successfully (it may have detected nothing)
+	DiagnosticOK = "ok"
+	// the fingerprint returned an error
+	DiagnosticFailed = "failed"
+	// the fingerprint exceeded a budget (its results may be partial)
+	DiagnosticBudgetExceeded = "budget-exceeded"
+)
+
+// writeDiagnostic writes the diagnostic of the fingerprint that ran for that duration and returned err.
+//
+// The values of the diagnostic are:
+//   - status - DiagnosticOK, DiagnosticFailed or DiagnosticBudgetExceeded
+//   - error - the error of the fingerprint (or the budgets that it exceeded)
+//   - duration-ms - the duration of the fingerprint in milliseconds
+//   - results - the number of results written by the fingerprint
+//   - bytes-read, zip-entries, nested-jar-depth - the usage of the budgets by the fingerprint
+//   - exceeded-budgets - the names of the exceeded budgets (comma-separated)
+func writeDiagnostic(ctx *Context, err error, duration time.Duration) error {
+	values := map[string]string{
+		"status":      DiagnosticOK,
+		"duration-ms": strconv.FormatInt(duration.Milliseconds(), 10),
+		"results":     strconv.Itoa(int(ctx.results.Load())),
+	}
+	if err != nil {
+		values["status"] = DiagnosticFailed
+		values["error"] = err.Error()
+	}
+
+	if ctx.budgets {
+		usage := utils.CurrentBudgetUsage()
+		values["bytes-read"] = strconv.FormatInt(usage.BytesRead, 10)
+		values["zip-entries"] = strconv.Itoa(usage.ZipEntries)
+		values["nested-jar-depth"] = strconv.Itoa(usage.NestedJarDepth)
+
+		names, messages := []string{}, []string{}
+		for _, exceeded := range utils.ExceededBudgets() {
+			log.Printf("⚠️ %s\n", exceeded)
+			if !slices.Contains(names, exceeded.Budget) {
+				names = append(names, exceeded.Budget)
+			}
+			messages = append(messages, exceeded.Error())
+		}
+		if len(names) > 0 {
+			values["exceeded-budgets"] = strings.Join(names, ",")
+			// the fingerprint stopped because of the budget (or ignored the files that exceed it)
+			var budgetErr *utils.BudgetExceededError
+			if err == nil || errors.As(err, &budgetErr) {
+				values["status"] = DiagnosticBudgetExceeded
+			}
+			if err == nil {
+				values["error"] = strings.Join(messages, "; ")
+			}
+		}
+	}
+
+	// the diagnostic is written even if the fingerprint is abandoned
+	result := Result{
+		Version:     ResultVersion,
+		Fingerprint: ctx.fingerprint,
+		Kind:        KindDiagnostic,
+		Values:      values,
+		Confidence:  ConfidenceHigh,
+	}
+	if err := writeResult(ctx.OutputDir, result); err != nil {
+		return fmt.Errorf("unable to write %s result: %w", KindDiagnostic, err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package fingerprint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"keyvalue"
+
+	"fingerprints/pkg/utils"
+)
+
+// FileRules fingerprints the runtimes detected by the file rules of the configuration.
+//
+// A file rule reads the version of a runtime from a file of the container (a properties file, a JSON document
+// or any file matched by a regex) so that a runtime can be detected without a dedicated fingerprint.
+type FileRules struct{}
+
+func init() {
+	Register(&FileRules{})
+}
+
+func (*FileRules) Name() string {
+	return "file-rules"
+}
+
+func (*FileRules) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, rule := range config.Fingerprints.FileRules {
+		if len(rule.ProcessNames) > 0 && !slices.Contains(rule.ProcessNames, process.Name) {
+			continue
+		}
+		version, evidence, found, err := evaluateFileRule(ctx.Root, rule, process)
+		if err != nil {
+			// an invalid rule does not prevent the other rules from running
+			err = fmt.Errorf("invalid file rule %s: %w", rule.RuntimeName, err)
+			log.Printf("⚠️ %s\n", err)
+			errs = append(errs, err)
+			continue
+		}
+		if !found {
+			continue
+		}
+		log.Printf("Found %s %s from %s\n", rule.RuntimeName, version, evidence.Path)
+		entries := map[string]string{rule.RuntimeName: version}
+		if err := ctx.Write("file-rules-fingerprints", entries, fileRuleConfidence(rule), evidence); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fileRuleConfidence returns the confidence of the runtimes detected by the rule
+func fileRuleConfidence(rule utils.FileRule) Confidence {
+	switch rule.Extract {
+	case utils.FileRuleProperties, utils.FileRuleJSON:
+		return ConfidenceHigh
+	default:
+		// the content of the file has no defined format (or is not read)
+		return ConfidenceMedium
+	}
+}
+
+// evaluateFileRule returns the version of the runtime read by the rule (empty for an exists rule)
+// from the first file matching its paths that gives a version
+func evaluateFileRule(root string, rule utils.FileRule, process utils.ProcessContext) (string, Evidence, bool, error) {
+	switch rule.Extract {
+	case utils.FileRuleExists, utils.FileRuleProperties, utils.FileRuleJSON:
+	case utils.FileRuleRegex:
+		if rule.CompiledRegex == nil {
+			return "", Evidence{}, false, fmt.Errorf("missing regex")
+		}
+	default:
+		return "", Evidence{}, false, fmt.Errorf("unsupported extract %q", rule.Extract)
+	}
+	baseDir, err := fileRuleBaseDir(root, rule.RelativeTo, process)
+	if err != nil {
+		return "", Evidence{}, false, err
+	}
+
+	for _, pattern := range rule.Paths {
+		if !filepath.IsAbs(pattern) {
+			if baseDir == "" {
+				continue
+			}
+			pattern = filepath.Join(baseDir, pattern)
+		}
+		for _, path := range utils.Glob(root, pattern) {
+			evidence := Evidence{Type: EvidenceFile, Path: path, Detail: rule.Key}
+			if rule.Entry != "" {
+				evidence = Evidence{Type: EvidenceJarEntry, Path: path, Detail: rule.Entry}
+			}
+			if rule.Extract == utils.FileRuleExists && rule.Entry == "" {
+				return "", evidence, true, nil
+			}
+
+			var content []byte
+			if rule.Entry != "" {
+				content, err = utils.ReadJarEntry(root, path, rule.Entry)
+			} else {
+				content, err = utils.ReadFile(root, path)
+			}
+			if err != nil {
+				log.Printf("Unable to read %s: %s\n", path, err)
+				continue
+			}
+			if version, found := extractFileRuleVersion(rule, content); found {
+				return version, evidence, true, nil
+			}
+		}
+	}
+	return "", Evidence{}, false, nil
+}
+
+// fileRuleBaseDir returns the directory that the relative paths of a file rule (or of the files of a plugin)
+// are resolved against (empty if the process does not have that directory)
+func fileRuleBaseDir(root string, relativeTo string, process utils.ProcessContext) (string, error) {
+	switch relativeTo {
+	case "", utils.FileRuleRelativeToRoot:
+		return "/", nil
+	case utils.FileRuleRelativeToCwd:
+		return process.Cwd, nil
+	case utils.FileRuleRelativeToInstallDir:
+		executable := process.Executable()
+		if !strings.Contains(executable, "/") {
+			path, err := utils.FindExecutableInPath(root, executable, process.Env("PATH"))
+			if err != nil {
+				return "", nil
+			}
+			executable = path
+		} else if !filepath.IsAbs(executable) {
+			executable = filepath.Join(process.Cwd, executable)
+		}
+		// the executable in the PATH is often a symbolic link to the executable of the installation
+		if resolved, err := utils.EvalSymlinks(root, executable); err == nil {
+			executable = resolved
+		}
+		return filepath.Dir(filepath.Dir(executable)), nil
+	default:
+		return "", fmt.Errorf("unsupported relative-to %q", relativeTo)
+	}
+}
+
+// extractFileRuleVersion reads the version of the runtime from the content of the file of the rule
+func extractFileRuleVersion(rule utils.FileRule, content []byte) (string, bool) {
+	switch rule.Extract {
+	case utils.FileRuleExists:
+		return "", true
+	case utils.FileRuleProperties:
+		properties, err := keyvalue.Read(bytes.NewReader(content))
+		if err != nil {
+			return "", false
+		}
+		version, found := properties[rule.Key]
+		return version, found
+	case utils.FileRuleJSON:
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		var document any
+		if err := decoder.Decode(&document); err != nil {
+			return "", false
+		}
+		version, found := lookupJSON(document, rule.Key)
+		return version, found
+	default:
+		return matchNamedGroup(rule.CompiledRegex, "version", string(content))
+	}
+}
+
+// lookupJSON returns the string or number at the dotted path of the JSON document
+func lookupJSON(document any, path string) (string, bool) {
+	for _, key := range strings.Split(path, ".") {
+		object, ok := document.(map[string]any)
+		if !ok {
+			return "", false
+		}
+		if document, ok = object[key]; !ok {
+			return "", false
+		}
+	}
+	switch value := document.(type) {
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	default:
+		return "", false
+	}
+}
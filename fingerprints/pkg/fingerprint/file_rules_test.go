@@ -0,0 +1,178 @@
+package fingerprint
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"fingerprints/pkg/utils"
+)
+
+const fileRulesTestConfig = `
+[[fingerprints.file-rules]]
+runtime-name = "PostgreSQL"
+process-names = ["postgres"]
+paths = ["/var/lib/pgsql/data/PG_VERSION", "/var/lib/postgresql/*/data/PG_VERSION"]
+extract = "regex"
+regex = '^(?P<version>\S+)'
+
+[[fingerprints.file-rules]]
+runtime-name = "Open Liberty"
+relative-to = "install-dir"
+paths = ["lib/versions/openliberty.properties"]
+extract = "properties"
+key = "com.ibm.websphere.productVersion"
+
+[[fingerprints.file-rules]]
+runtime-name = "WordPress"
+relative-to = "cwd"
+paths = ["wp-includes/version.php"]
+extract = "regex"
+regex = "\\$wp_version = '(?P<version>[^']+)'"
+
+[[fingerprints.file-rules]]
+runtime-name = "Apache Tomcat"
+relative-to = "cwd"
+paths = ["lib/catalina.jar"]
+entry = "org/apache/catalina/util/ServerInfo.properties"
+extract = "regex"
+regex = 'server.number=(?P<version>\S+)'
+
+[[fingerprints.file-rules]]
+runtime-name = "Express"
+relative-to = "cwd"
+paths = ["node_modules/express/package.json"]
+extract = "json"
+key = "version"
+
+[[fingerprints.file-rules]]
+runtime-name = "Supervisor"
+paths = ["/etc/supervisord.conf"]
+extract = "exists"
+`
+
+// readFileRulesResults returns the values and the evidence of the file-rules results of the output directory
+func readFileRulesResults(t *testing.T, outputDir string) (map[string]string, []Evidence) {
+	files, _ := filepath.Glob(filepath.Join(outputDir, "file-rules-fingerprints.file-rules.*.json"))
+	values := map[string]string{}
+	evidence := []Evidence{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		var result Result
+		assert.NoError(t, json.Unmarshal(content, &result))
+		for k, v := range result.Values {
+			values[k] = v
+		}
+		evidence = append(evidence, result.Evidence...)
+	}
+	sort.Slice(evidence, func(i, j int) bool { return evidence[i].Path < evidence[j].Path })
+	return values, evidence
+}
+
+func TestFileRules(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/opt/ol/wlp/bin/server", "#!/bin/sh\n")
+	writeRootFile(t, root, "/opt/ol/wlp/lib/versions/openliberty.properties", "com.ibm.websphere.productId=io.openliberty\ncom.ibm.websphere.productVersion=24.0.0.6\n")
+	writeRootFile(t, root, "/var/lib/postgresql/16/data/PG_VERSION", "16\n")
+	writeRootFile(t, root, "/app/node_modules/express/package.json", `{"name": "express", "version": "4.19.2"}`)
+	writeRootFile(t, root, "/etc/supervisord.conf", "[supervisord]\n")
+	writeRootFile(t, root, "/app/wp-includes/version.php", "<?php\n$wp_version = '6.5.4';\n")
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", fileRulesTestConfig)
+	writeProcess(t, outputDir, "/app", map[string]string{"PATH": "/usr/bin:/opt/ol/wlp/bin"}, "server", "run")
+
+	// the PostgreSQL rule does not apply to the process
+	assert.NoError(t, Run("file-rules", []string{"--root", root, outputDir}))
+	values, evidence := readFileRulesResults(t, outputDir)
+	assert.Equal(t, map[string]string{"Open Liberty": "24.0.0.6", "WordPress": "6.5.4", "Express": "4.19.2", "Supervisor": ""}, values)
+	assert.Equal(t, []Evidence{
+		{Type: EvidenceFile, Path: "/app/node_modules/express/package.json", Detail: "version"},
+		{Type: EvidenceFile, Path: "/app/wp-includes/version.php"},
+		{Type: EvidenceFile, Path: "/etc/supervisord.conf"},
+		{Type: EvidenceFile, Path: "/opt/ol/wlp/lib/versions/openliberty.properties", Detail: "com.ibm.websphere.productVersion"},
+	}, evidence)
+
+	outputDir = t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", fileRulesTestConfig)
+	writeProcess(t, outputDir, "/", nil, "/usr/bin/postgres")
+	assert.NoError(t, Run("file-rules", []string{"--root", root, outputDir}))
+	values, _ = readFileRulesResults(t, outputDir)
+	assert.Equal(t, map[string]string{"PostgreSQL": "16", "Supervisor": ""}, values)
+}
+
+func TestFileRulesJarEntry(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "usr/local/tomcat/lib"), 0755))
+	file, err := os.Create(filepath.Join(root, "usr/local/tomcat/lib/catalina.jar"))
+	assert.NoError(t, err)
+	w := zip.NewWriter(file)
+	entry, err := w.Create("org/apache/catalina/util/ServerInfo.properties")
+	assert.NoError(t, err)
+	_, err = entry.Write([]byte("server.info=Apache Tomcat/10.1.24\nserver.number=10.1.24.0\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, file.Close())
+	// the files of the rules are not looked up in the subdirectories of the cwd
+	writeRootFile(t, root, "/usr/local/tomcat/webapps/wp-includes/version.php", "<?php\n$wp_version = '6.5.4';\n")
+
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", fileRulesTestConfig)
+	writeProcess(t, outputDir, "/usr/local/tomcat", nil, "java", "-cp", "bin/bootstrap.jar", "org.apache.catalina.startup.Bootstrap")
+	assert.NoError(t, Run("file-rules", []string{"--root", root, outputDir}))
+	values, evidence := readFileRulesResults(t, outputDir)
+	assert.Equal(t, map[string]string{"Apache Tomcat": "10.1.24.0"}, values)
+	assert.Equal(t, []Evidence{{Type: EvidenceJarEntry, Path: "/usr/local/tomcat/lib/catalina.jar", Detail: "org/apache/catalina/util/ServerInfo.properties"}}, evidence)
+}
+
+func TestExtractFileRuleVersion(t *testing.T) {
+	version, found := extractFileRuleVersion(utils.FileRule{Extract: utils.FileRuleJSON, Key: "engines.node"}, []byte(`{"engines": {"node": ">=18"}, "version": 2}`))
+	assert.True(t, found)
+	assert.Equal(t, ">=18", version)
+
+	version, found = extractFileRuleVersion(utils.FileRule{Extract: utils.FileRuleJSON, Key: "version"}, []byte(`{"version": 2}`))
+	assert.True(t, found)
+	assert.Equal(t, "2", version)
+
+	_, found = extractFileRuleVersion(utils.FileRule{Extract: utils.FileRuleJSON, Key: "engines.node.version"}, []byte(`{"engines": {"node": ">=18"}}`))
+	assert.False(t, found)
+
+	_, found = extractFileRuleVersion(utils.FileRule{Extract: utils.FileRuleProperties, Key: "version"}, []byte("name=app\n"))
+	assert.False(t, found)
+
+	rule := utils.FileRule{Extract: utils.FileRuleRegex, CompiledRegex: regexp.MustCompile(`version (?P<version>[0-9.]+)`)}
+	version, found = extractFileRuleVersion(rule, []byte("supervisor version 4.2.5\n"))
+	assert.True(t, found)
+	assert.Equal(t, "4.2.5", version)
+	_, found = extractFileRuleVersion(rule, []byte("supervisor\n"))
+	assert.False(t, found)
+}
+
+func TestFileRulesInvalidRule(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/etc/supervisord.conf", "[supervisord]\n")
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", `
+[[fingerprints.file-rules]]
+runtime-name = "App"
+paths = ["/app"]
+extract = "xml"
+
+[[fingerprints.file-rules]]
+runtime-name = "Supervisor"
+paths = ["/etc/supervisord.conf"]
+extract = "exists"
+`)
+	writeProcess(t, outputDir, "/", nil, "/usr/bin/app")
+
+	// the invalid rule does not prevent the next rule from running
+	assert.EqualError(t, Run("file-rules", []string{"--root", root, outputDir}), "file-rules fingerprint failed: invalid file rule App: unsupported extract \"xml\"")
+	values, _ := readFileRulesResults(t, outputDir)
+	assert.Equal(t, map[string]string{"Supervisor": ""}, values)
+}
@@ -0,0 +1,246 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"fingerprints/pkg/utils"
+)
+
+// Fingerprinter inspects a container process and writes its results to the output directory.
+//
+// Fingerprinters are registered with Register and are run as subcommands of the fpr binary:
+//
+//	fpr <name> [--root <dir>] <output-dir>
+//
+// The output directory contains the configuration (config.toml) and the process context (process.json)
+// that describes the process to inspect (see Context.Process).
+// The paths of the container are accessed through the helpers of the utils package that take the root
+// of the container filesystem (Context.Root) so that the fingerprints can also run against
+// an unpacked root filesystem.
+type Fingerprinter interface {
+	// Name of the fingerprint (the subcommand of the fpr binary)
+	Name() string
+	// Fingerprint inspects the container process.
+	// A fingerprint that does not apply to the process returns nil without writing any entries.
+	Fingerprint(ctx *Context) error
+}
+
+var registry = make(map[string]Fingerprinter)
+
+// Register adds the fingerprinter to the registry. It panics if a fingerprinter with the same name is already registered.
+func Register(f Fingerprinter) {
+	if _, exists := registry[f.Name()]; exists {
+		panic(fmt.Sprintf("fingerprint %s is already registered", f.Name()))
+	}
+	registry[f.Name()] = f
+}
+
+// Get returns the registered fingerprinter with that name
+func Get(name string) (Fingerprinter, bool) {
+	f, found := registry[name]
+	return f, found
+}
+
+// Names returns the sorted names of the registered fingerprinters
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Usage returns the command line of the fingerprinter
+func Usage(f Fingerprinter) string {
+	return "fpr " + f.Name() + " [--root <dir>] <output-dir>"
+}
+
+// Run runs the named fingerprint. The arguments are the options (`--root <dir>`) and the output directory.
+func Run(name string, args []string) error {
+	f, found := Get(name)
+	if !found {
+		return fmt.Errorf("unknown fingerprint %q (available fingerprints: %s)", name, strings.Join(Names(), ", "))
+	}
+	root, args, err := parseOptions(args)
+	if err != nil {
+		return fmt.Errorf("%w\nusage: %s", err, Usage(f))
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("missing output directory\nusage: %s", Usage(f))
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("unexpected arguments %q\nusage: %s", args[1:], Usage(f))
+	}
+	ctx := &Context{
+		OutputDir:   args[0],
+		Root:        root,
+		fingerprint: name,
+	}
+
+	startTime := time.Now()
+	if root != "" {
+		log.Printf("🔎 Running the %s fingerprint on the root filesystem %s to %s\n", name, root, ctx.OutputDir)
+	} else {
+		log.Printf("🔎 Running the %s fingerprint to %s\n", name, ctx.OutputDir)
+	}
+
+	err = runWithBudgets(f, ctx)
+
+	duration := time.Since(startTime)
+	log.Printf("🕑 %s fingerprint executed in time: %s\n", name, duration)
+	if err := writeDiagnostic(ctx, err, duration); err != nil {
+		log.Printf("⚠️ %s\n", err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s fingerprint failed: %w", name, err)
+	}
+	return nil
+}
+
+// runWithBudgets runs the fingerprint within the budgets of the configuration.
+// A fingerprint that exceeds its timeout is abandoned: its results written after the timeout are discarded.
+func runWithBudgets(f Fingerprinter, ctx *Context) error {
+	if _, ok := f.(composite); ok {
+		// the budgets apply to each fingerprint that it runs
+		return runFingerprint(f, ctx)
+	}
+	ctx.budgets = true
+	budgets := utils.DefaultBudgets
+	if config, err := ctx.Config(); err == nil {
+		budgets = config.Fingerprints.Budgets.WithDefaults()
+	}
+	utils.StartBudgets(budgets)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runFingerprint(f, ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(budgets.Timeout):
+		ctx.abandoned.Store(true)
+		err = utils.BudgetExceeded(utils.BudgetTimeout, budgets.Timeout.String(), "")
+	}
+	return err
+}
+
+// runFingerprint runs the fingerprint and returns its panic as an error
+// so that an unexpected failure is reported like the other errors
+func runFingerprint(f Fingerprinter, ctx *Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("unexpected error: %v", r)
+		}
+	}()
+	return f.Fingerprint(ctx)
+}
+
+// composite is implemented by the fingerprints that run other fingerprints
+type composite interface {
+	runsFingerprints()
+}
+
+// parseOptions returns the root filesystem set by the `--root <dir>` (or `--root=<dir>`) option
+// and the arguments that follow the options
+func parseOptions(args []string) (string, []string, error) {
+	root := ""
+	for len(args) > 0 {
+		if value, found := strings.CutPrefix(args[0], "--root="); found {
+			root = value
+			args = args[1:]
+		} else if args[0] == "--root" {
+			if len(args) < 2 {
+				return "", nil, fmt.Errorf("missing value of the --root option")
+			}
+			root = args[1]
+			args = args[2:]
+		} else {
+			break
+		}
+	}
+	if root != "" {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return "", nil, fmt.Errorf("root filesystem %s is not a directory", root)
+		}
+	}
+	return root, args, nil
+}
+
+// Context gives access to the process context, the configuration and the output directory of a fingerprint
+type Context struct {
+	// Directory to write the fingerprints to (it also contains the config.toml configuration
+	// and the process.json process context)
+	OutputDir string
+	// Directory containing the root filesystem of the container.
+	// It is empty when the fingerprint runs in the filesystem of the container.
+	// The paths of the container (including the paths in the arguments) must be accessed through the helpers
+	// of the utils package that resolve them in that root (utils.Open, utils.ReadFile, utils.Glob...).
+	Root string
+
+	fingerprint string
+	config      *utils.Config
+	process     *utils.ProcessContext
+	// set when the fingerprint exceeds its timeout
+	abandoned atomic.Bool
+	// set when the fingerprint runs within its own budgets
+	budgets bool
+	// number of results written
+	results atomic.Int32
+}
+
+// Config returns the configuration read from the config.toml file of the output directory.
+// The configuration is read only once.
+func (ctx *Context) Config() (utils.Config, error) {
+	if ctx.config == nil {
+		configPath := filepath.Join(ctx.OutputDir, "config.toml")
+		config, err := utils.GetConfig(configPath)
+		if err != nil {
+			return utils.Config{}, fmt.Errorf("unable to read configuration %s: %w", configPath, err)
+		}
+		ctx.config = &config
+	}
+	return *ctx.config, nil
+}
+
+// Process returns the process context read from the process.json file of the output directory.
+// The process context is read only once.
+func (ctx *Context) Process() (utils.ProcessContext, error) {
+	if ctx.process == nil {
+		processPath := filepath.Join(ctx.OutputDir, utils.ProcessContextFile)
+		process, err := utils.GetProcessContext(processPath)
+		if err != nil {
+			return utils.ProcessContext{}, fmt.Errorf("unable to read process context %s: %w", processPath, err)
+		}
+		ctx.process = &process
+	}
+	return *ctx.process, nil
+}
+
+// Write writes the values of that kind as a fingerprint result to the output directory
+func (ctx *Context) Write(kind string, values map[string]string, confidence Confidence, evidence ...Evidence) error {
+	if ctx.abandoned.Load() {
+		return fmt.Errorf("unable to write %s result: the fingerprint exceeded its timeout", kind)
+	}
+	result := Result{
+		Version:     ResultVersion,
+		Fingerprint: ctx.fingerprint,
+		Kind:        kind,
+		Values:      values,
+		Evidence:    evidence,
+		Confidence:  confidence,
+	}
+	if err := writeResult(ctx.OutputDir, result); err != nil {
+		return fmt.Errorf("unable to write %s result: %w", kind, err)
+	}
+	ctx.results.Add(1)
+	return nil
+}
@@ -0,0 +1,346 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"fingerprints/pkg/utils"
+)
+
+func TestMain(m *testing.M) {
+	// the process fingerprint runs each fingerprint in a child process of the test binary, called like fpr
+	if filepath.Base(os.Args[0]) == "fpr" {
+		if err := Run(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("❌ %s\n", err)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+type testFingerprinter struct {
+	run func(ctx *Context) error
+}
+
+func (*testFingerprinter) Name() string {
+	return "test"
+}
+
+func (f *testFingerprinter) Fingerprint(ctx *Context) error {
+	return f.run(ctx)
+}
+
+func withTestFingerprinter(t *testing.T, f *testFingerprinter) {
+	Register(f)
+	t.Cleanup(func() { delete(registry, f.Name()) })
+}
+
+// writeProcess writes the process context of the process running that command line to the output directory
+func writeProcess(t *testing.T, outputDir string, cwd string, environ map[string]string, commandLine ...string) {
+	assert.NoError(t, utils.WriteProcessContext(outputDir, utils.ProcessContext{
+		Cwd:         cwd,
+		CommandLine: commandLine,
+		Environ:     environ,
+	}))
+}
+
+func TestRegistry(t *testing.T) {
+	assert.Contains(t, Names(), "os")
+	assert.Contains(t, Names(), "java-runtimes")
+
+	f, found := Get("os")
+	assert.True(t, found)
+	assert.Equal(t, "os", f.Name())
+
+	_, found = Get("unknown")
+	assert.False(t, found)
+
+	assert.Panics(t, func() { Register(&Os{}) })
+}
+
+func TestUsage(t *testing.T) {
+	assert.Equal(t, "fpr test [--root <dir>] <output-dir>", Usage(&testFingerprinter{}))
+}
+
+func TestContextProcess(t *testing.T) {
+	outputDir := t.TempDir()
+	ctx := &Context{OutputDir: outputDir}
+	_, err := ctx.Process()
+	assert.ErrorContains(t, err, "unable to read process context "+filepath.Join(outputDir, "process.json"))
+
+	uid := 1001
+	assert.NoError(t, utils.WriteProcessContext(outputDir, utils.ProcessContext{
+		PID:         42,
+		UID:         &uid,
+		Cwd:         "/app",
+		CommandLine: []string{"/usr/lib/jvm/java-17/bin/java", "-jar", "app.jar"},
+		Environ:     map[string]string{"PATH": "/usr/bin"},
+	}))
+	process, err := ctx.Process()
+	assert.NoError(t, err)
+	assert.Equal(t, utils.ProcessContext{
+		Version:     utils.ProcessContextVersion,
+		PID:         42,
+		UID:         &uid,
+		Name:        "java",
+		Cwd:         "/app",
+		CommandLine: []string{"/usr/lib/jvm/java-17/bin/java", "-jar", "app.jar"},
+		Environ:     map[string]string{"PATH": "/usr/bin"},
+	}, process)
+	assert.Equal(t, "/usr/lib/jvm/java-17/bin/java", process.Executable())
+	assert.Equal(t, "/usr/bin", process.Env("PATH"))
+	assert.Equal(t, "", process.Env("JAVA_HOME"))
+
+	// the process context is read only once
+	assert.NoError(t, os.Remove(filepath.Join(outputDir, "process.json")))
+	_, err = ctx.Process()
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(outputDir, "process.json"), []byte(`{"version": 2, "command-line": ["node"]}`), 0644))
+	_, err = (&Context{OutputDir: outputDir}).Process()
+	assert.ErrorContains(t, err, "unsupported process context version 2")
+	assert.NoError(t, os.WriteFile(filepath.Join(outputDir, "process.json"), []byte(`{"version": 1, "command-line": []}`), 0644))
+	_, err = (&Context{OutputDir: outputDir}).Process()
+	assert.ErrorContains(t, err, "the command line of the process is empty")
+}
+
+func TestRun(t *testing.T) {
+	outputDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(outputDir, "config.toml"), []byte(`
+[fingerprints]
+[[fingerprints.agents]]
+agent-name = "Test Agent"
+`), 0644)
+	assert.NoError(t, err)
+
+	withTestFingerprinter(t, &testFingerprinter{
+		run: func(ctx *Context) error {
+			config, err := ctx.Config()
+			if err != nil {
+				return err
+			}
+			process, err := ctx.Process()
+			if err != nil {
+				return err
+			}
+			return ctx.Write("test", map[string]string{
+				"executable": process.Executable(),
+				"agent":      config.Fingerprints.Agents[0].AgentName,
+			}, ConfidenceHigh, Evidence{Type: EvidenceCommandLine, Detail: process.Executable()})
+		},
+	})
+
+	writeProcess(t, outputDir, "/", nil, "/usr/bin/node")
+	err = Run("test", []string{outputDir})
+	assert.NoError(t, err)
+	// the fingerprint can run several times without overwriting its results
+	writeProcess(t, outputDir, "/", nil, "/usr/bin/python3")
+	err = Run("test", []string{outputDir})
+	assert.NoError(t, err)
+
+	resultFiles, err := filepath.Glob(filepath.Join(outputDir, "test.test.*.json"))
+	assert.NoError(t, err)
+	assert.Len(t, resultFiles, 2)
+	results := []Result{}
+	for _, resultFile := range resultFiles {
+		content, err := os.ReadFile(resultFile)
+		assert.NoError(t, err)
+		var result Result
+		assert.NoError(t, json.Unmarshal(content, &result))
+		results = append(results, result)
+	}
+	assert.ElementsMatch(t, []Result{
+		{
+			Version:     ResultVersion,
+			Fingerprint: "test",
+			Kind:        "test",
+			Values:      map[string]string{"agent": "Test Agent", "executable": "/usr/bin/node"},
+			Evidence:    []Evidence{{Type: EvidenceCommandLine, Detail: "/usr/bin/node"}},
+			Confidence:  ConfidenceHigh,
+		},
+		{
+			Version:     ResultVersion,
+			Fingerprint: "test",
+			Kind:        "test",
+			Values:      map[string]string{"agent": "Test Agent", "executable": "/usr/bin/python3"},
+			Evidence:    []Evidence{{Type: EvidenceCommandLine, Detail: "/usr/bin/python3"}},
+			Confidence:  ConfidenceHigh,
+		},
+	}, results)
+
+	err = Run("test", []string{})
+	assert.EqualError(t, err, "missing output directory\nusage: fpr test [--root <dir>] <output-dir>")
+	err = Run("test", []string{outputDir, "/usr/bin/node"})
+	assert.EqualError(t, err, "unexpected arguments [\"/usr/bin/node\"]\nusage: fpr test [--root <dir>] <output-dir>")
+
+	err = Run("test", []string{t.TempDir()})
+	assert.ErrorContains(t, err, "test fingerprint failed: unable to read configuration")
+
+	err = Run("unknown", []string{outputDir})
+	assert.ErrorContains(t, err, `unknown fingerprint "unknown"`)
+}
+
+func TestRunError(t *testing.T) {
+	withTestFingerprinter(t, &testFingerprinter{
+		run: func(ctx *Context) error {
+			return errors.New("boom")
+		},
+	})
+	err := Run("test", []string{t.TempDir()})
+	assert.EqualError(t, err, "test fingerprint failed: boom")
+}
+
+func TestRunWithRoot(t *testing.T) {
+	root := t.TempDir()
+	var roots []string
+	withTestFingerprinter(t, &testFingerprinter{
+		run: func(ctx *Context) error {
+			roots = append(roots, ctx.Root)
+			return nil
+		},
+	})
+
+	assert.NoError(t, Run("test", []string{t.TempDir()}))
+	assert.NoError(t, Run("test", []string{"--root", root, t.TempDir()}))
+	assert.NoError(t, Run("test", []string{"--root=" + root, t.TempDir()}))
+	assert.Equal(t, []string{"", root, root}, roots)
+
+	err := Run("test", []string{"--root"})
+	assert.ErrorContains(t, err, "missing value of the --root option")
+	err = Run("test", []string{"--root", filepath.Join(root, "missing"), t.TempDir()})
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
+// readDiagnostics returns the values of the diagnostics written by the test fingerprint (and removes them)
+func readDiagnostics(t *testing.T, outputDir string) []map[string]string {
+	files, err := filepath.Glob(filepath.Join(outputDir, "diagnostic.test.*.json"))
+	assert.NoError(t, err)
+	diagnostics := []map[string]string{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		var result Result
+		assert.NoError(t, json.Unmarshal(content, &result))
+		// the duration depends on the host
+		assert.Contains(t, result.Values, "duration-ms")
+		delete(result.Values, "duration-ms")
+		diagnostics = append(diagnostics, result.Values)
+		assert.NoError(t, os.Remove(file))
+	}
+	return diagnostics
+}
+
+func TestRunDiagnostics(t *testing.T) {
+	outputDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(outputDir, "config.toml"), []byte("[fingerprints]\n"), 0644))
+	// the mode of the test fingerprint is the command line of the process
+	mode := func(ctx *Context) string {
+		process, _ := ctx.Process()
+		return process.Executable()
+	}
+	run := func(m string) error {
+		writeProcess(t, outputDir, "/", nil, m)
+		return Run("test", []string{outputDir})
+	}
+	withTestFingerprinter(t, &testFingerprinter{
+		run: func(ctx *Context) error {
+			switch mode(ctx) {
+			case "fail":
+				return errors.New("boom")
+			case "panic":
+				var values map[string]string
+				values["key"] = "value"
+			case "detect":
+				return ctx.Write("test", map[string]string{"key": "value"}, ConfidenceHigh)
+			}
+			return nil
+		},
+	})
+	usage := map[string]string{"bytes-read": "0", "zip-entries": "0", "nested-jar-depth": "0"}
+	with := func(values map[string]string) map[string]string {
+		for k, v := range usage {
+			values[k] = v
+		}
+		return values
+	}
+
+	assert.NoError(t, run("detect"))
+	assert.Equal(t, []map[string]string{with(map[string]string{"status": "ok", "results": "1"})}, readDiagnostics(t, outputDir))
+
+	// nothing detected
+	assert.NoError(t, run("none"))
+	assert.Equal(t, []map[string]string{with(map[string]string{"status": "ok", "results": "0"})}, readDiagnostics(t, outputDir))
+
+	assert.EqualError(t, run("fail"), "test fingerprint failed: boom")
+	assert.Equal(t, []map[string]string{with(map[string]string{"status": "failed", "results": "0", "error": "boom"})}, readDiagnostics(t, outputDir))
+
+	// the panic of a fingerprint is reported as an error
+	err := run("panic")
+	assert.ErrorContains(t, err, "test fingerprint failed: unexpected error: assignment to entry in nil map")
+	diagnostics := readDiagnostics(t, outputDir)
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, "failed", diagnostics[0]["status"])
+	assert.Contains(t, diagnostics[0]["error"], "unexpected error: assignment to entry in nil map")
+}
+
+func TestRunBudgetExceeded(t *testing.T) {
+	outputDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(outputDir, "config.toml"), []byte(`
+[fingerprints.budgets]
+timeout = "50ms"
+max-file-bytes = 4
+`), 0644)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(outputDir, "large"), []byte("12345"), 0644))
+
+	release := make(chan struct{})
+	defer close(release)
+	// the budgets of the configuration stay set after the fingerprint has run
+	t.Cleanup(func() { utils.StartBudgets(utils.DefaultBudgets) })
+	withTestFingerprinter(t, &testFingerprinter{
+		run: func(ctx *Context) error {
+			if process, _ := ctx.Process(); process.Executable() == "stall" {
+				<-release
+				return ctx.Write("test", map[string]string{}, ConfidenceHigh)
+			}
+			// the fingerprint stops cleanly when a helper exceeds a budget
+			if _, err := utils.ReadFile(ctx.OutputDir, "/large"); err != nil {
+				return nil
+			}
+			return ctx.Write("test", map[string]string{}, ConfidenceHigh)
+		},
+	})
+
+	writeProcess(t, outputDir, "/", nil, "read")
+	assert.NoError(t, Run("test", []string{outputDir}))
+	assert.Equal(t, []map[string]string{{
+		"status":           "budget-exceeded",
+		"error":            "budget exceeded: max-file-bytes of /large (limit 4)",
+		"exceeded-budgets": "max-file-bytes",
+		"results":          "0",
+		"bytes-read":       "4",
+		"zip-entries":      "0",
+		"nested-jar-depth": "0",
+	}}, readDiagnostics(t, outputDir))
+
+	writeProcess(t, outputDir, "/", nil, "stall")
+	err = Run("test", []string{outputDir})
+	assert.EqualError(t, err, "test fingerprint failed: budget exceeded: timeout (limit 50ms)")
+	assert.Equal(t, []map[string]string{{
+		"status":           "budget-exceeded",
+		"error":            "budget exceeded: timeout (limit 50ms)",
+		"exceeded-budgets": "timeout",
+		"results":          "0",
+		"bytes-read":       "0",
+		"zip-entries":      "0",
+		"nested-jar-depth": "0",
+	}}, readDiagnostics(t, outputDir))
+	files, _ := filepath.Glob(filepath.Join(outputDir, "test.test.*.json"))
+	assert.Empty(t, files)
+}
@@ -0,0 +1,96 @@
+package fingerprint
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// javaApplication is the Java application run by a Java process
+type javaApplication struct {
+	// executable jar (or main jar of the runtime whose main class is run). It is empty if no jar is detected.
+	jar string
+	// classpath of a classpath-based application (its entries are absolute paths)
+	classpath string
+	// main class of a classpath-based application
+	mainClass string
+}
+
+// getJavaApplication returns the Java application of the process of the context.
+// It returns false if the process is not a Java process running an executable jar or a classpath.
+func getJavaApplication(ctx *Context) (javaApplication, bool, error) {
+	process, err := ctx.Process()
+	if err != nil {
+		return javaApplication{}, false, err
+	}
+	if !strings.HasSuffix(process.Name, "java") {
+		return javaApplication{}, false, nil
+	}
+	config, err := ctx.Config()
+	if err != nil {
+		return javaApplication{}, false, err
+	}
+	application, found := selectJavaApplication(config, process)
+	return application, found, nil
+}
+
+// selectJavaApplication returns the Java application (executable jar, classpath and main class) from the command line of the process
+func selectJavaApplication(config utils.Config, process utils.ProcessContext) (javaApplication, bool) {
+	commandLine := process.CommandLine
+	absolutePath := func(path string) string {
+		if path == "" || filepath.IsAbs(path) {
+			return path
+		}
+		return process.Cwd + "/" + path
+	}
+
+	// "java -jar" process
+	if i := slices.Index(commandLine, "-jar"); i >= 0 && i+1 < len(commandLine) {
+		return javaApplication{jar: absolutePath(commandLine[i+1])}, true
+	}
+
+	// Java classpath-based process
+	classpathIdx := slices.IndexFunc(commandLine, func(arg string) bool { return arg == "-classpath" || arg == "-cp" })
+	if classpathIdx < 0 || classpathIdx+1 >= len(commandLine) {
+		return javaApplication{}, false
+	}
+	entries := []string{}
+	for _, entry := range strings.Split(commandLine[classpathIdx+1], ":") {
+		if entry != "" {
+			entries = append(entries, absolutePath(entry))
+		}
+	}
+	application := javaApplication{classpath: strings.Join(entries, ":")}
+	// the main class is the first argument after the classpath that is not a JVM option (or the value of an option)
+	args := commandLine[classpathIdx+2:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if slices.Contains(optionsWithValue, arg) {
+			i++
+			continue
+		}
+		// the argument files (@<file>) of the launcher contain options
+		if strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "@") {
+			continue
+		}
+		application.mainClass = arg
+		break
+	}
+
+	// find the main jar of the runtimes whose main class is in the command line
+	for _, javaConfig := range config.Fingerprints.Java {
+		if javaConfig.MainJar == "" || !slices.Contains(commandLine, javaConfig.MainClass) {
+			continue
+		}
+		for _, jar := range entries {
+			if strings.Contains(jar, javaConfig.MainJar) {
+				application.jar = jar
+				return application, true
+			}
+		}
+	}
+	// no main jar is detected but the classpath can still be inspected
+	return application, true
+}
@@ -0,0 +1,122 @@
+package fingerprint
+
+import (
+	"archive/zip"
+	"log"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// Packages of the Java EE APIs that have been renamed in Jakarta EE 9
+var javaEEPackages = []string{
+	"servlet/",
+	"persistence/",
+	"ws/rs/",
+}
+
+// JavaNamespace fingerprints the namespace (javax or jakarta) of the Java EE APIs used by the application
+type JavaNamespace struct{}
+
+func init() {
+	Register(&JavaNamespace{})
+}
+
+func (*JavaNamespace) Name() string {
+	return "java-namespace"
+}
+
+func (*JavaNamespace) Fingerprint(ctx *Context) error {
+	application, found, err := getJavaApplication(ctx)
+	if err != nil || !found {
+		return err
+	}
+	// the jar can be empty if the application is not run from a jar
+	inspectedJar := application.jar
+	classpath := application.classpath
+
+	log.Printf("🔎 Fingerprinting the Java EE namespace from %s (classpath: %s)\n", inspectedJar, classpath)
+
+	usesJavax := false
+	usesJakarta := false
+	// the first jar that contains classes of each namespace
+	evidence := []Evidence{}
+
+	for _, jar := range utils.GetClasspathJars(ctx.Root, inspectedJar, classpath) {
+		javax, jakarta := checkJarNamespaces(ctx.Root, jar)
+		if javax && !usesJavax {
+			evidence = append(evidence, Evidence{Type: EvidenceJarEntry, Path: jar, Detail: "javax"})
+		}
+		if jakarta && !usesJakarta {
+			evidence = append(evidence, Evidence{Type: EvidenceJarEntry, Path: jar, Detail: "jakarta"})
+		}
+		usesJavax = usesJavax || javax
+		usesJakarta = usesJakarta || jakarta
+		if usesJavax && usesJakarta {
+			break
+		}
+	}
+
+	entries := make(map[string]string)
+	switch {
+	case usesJavax && usesJakarta:
+		entries["java-ee-namespace"] = "mixed"
+	case usesJavax:
+		entries["java-ee-namespace"] = "javax"
+	case usesJakarta:
+		entries["java-ee-namespace"] = "jakarta"
+	}
+	if len(entries) > 0 {
+		return ctx.Write("java-namespace", entries, ConfidenceHigh, evidence...)
+	}
+	return nil
+}
+
+// checkJarNamespaces returns whether the jar (or any of its nested jars) contains classes
+// from the `javax` or `jakarta` namespaces of the Java EE APIs
+func checkJarNamespaces(root string, jarPath string) (bool, bool) {
+	r, err := utils.OpenJar(root, jarPath)
+	if err != nil {
+		return false, false
+	}
+	defer r.Close()
+
+	javax, jakarta := checkNamespaces(&r.Reader)
+	for _, file := range r.File {
+		if javax && jakarta {
+			break
+		}
+		if !strings.HasSuffix(file.Name, ".jar") {
+			continue
+		}
+		nestedJar, err := utils.ReadNestedJar(file, 1)
+		if err != nil {
+			continue
+		}
+		nestedJavax, nestedJakarta := checkNamespaces(nestedJar)
+		javax = javax || nestedJavax
+		jakarta = jakarta || nestedJakarta
+	}
+	return javax, jakarta
+}
+
+func checkNamespaces(r *zip.Reader) (bool, bool) {
+	javax := false
+	jakarta := false
+	for _, file := range r.File {
+		if !strings.HasSuffix(file.Name, ".class") {
+			continue
+		}
+		for _, pkg := range javaEEPackages {
+			if strings.HasPrefix(file.Name, "javax/"+pkg) {
+				javax = true
+			} else if strings.HasPrefix(file.Name, "jakarta/"+pkg) {
+				jakarta = true
+			}
+		}
+		if javax && jakarta {
+			break
+		}
+	}
+	return javax, jakarta
+}
@@ -0,0 +1,73 @@
+package fingerprint
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createJar returns the content of a jar with these entries
+func createJar(t *testing.T, entries map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	w := zip.NewWriter(&buffer)
+	for name, content := range entries {
+		entry, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = entry.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return buffer.Bytes()
+}
+
+func TestCheckNamespaces(t *testing.T) {
+	for _, test := range []struct {
+		entries []string
+		javax   bool
+		jakarta bool
+	}{
+		{entries: []string{"javax/servlet/http/HttpServlet.class"}, javax: true},
+		{entries: []string{"jakarta/persistence/Entity.class", "com/acme/Main.class"}, jakarta: true},
+		{entries: []string{"javax/ws/rs/Path.class", "jakarta/servlet/Filter.class"}, javax: true, jakarta: true},
+		// the javax packages of the JDK have not been renamed
+		{entries: []string{"javax/crypto/Cipher.class", "javax/xml/XMLConstants.class"}},
+		// only the classes are checked
+		{entries: []string{"javax/servlet/LocalStrings.properties", "jakarta/servlet/"}},
+	} {
+		entries := map[string][]byte{}
+		for _, entry := range test.entries {
+			entries[entry] = nil
+		}
+		content := createJar(t, entries)
+		r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		assert.NoError(t, err)
+		javax, jakarta := checkNamespaces(r)
+		assert.Equal(t, test.javax, javax, test.entries)
+		assert.Equal(t, test.jakarta, jakarta, test.entries)
+	}
+}
+
+func TestCheckJarNamespaces(t *testing.T) {
+	root := t.TempDir()
+	// Spring Boot fat jar with the APIs in nested jars
+	writeRootFile(t, root, "/app/app.jar", string(createJar(t, map[string][]byte{
+		"com/acme/Main.class":                        nil,
+		"BOOT-INF/lib/jakarta.servlet-api-6.0.0.jar": createJar(t, map[string][]byte{"jakarta/servlet/Servlet.class": nil}),
+		"BOOT-INF/lib/javax.persistence-api-2.2.jar": createJar(t, map[string][]byte{"javax/persistence/Entity.class": nil}),
+	})))
+	writeRootFile(t, root, "/app/lib.jar", string(createJar(t, map[string][]byte{"com/acme/Lib.class": nil})))
+
+	javax, jakarta := checkJarNamespaces(root, "/app/app.jar")
+	assert.True(t, javax)
+	assert.True(t, jakarta)
+
+	javax, jakarta = checkJarNamespaces(root, "/app/lib.jar")
+	assert.False(t, javax)
+	assert.False(t, jakarta)
+
+	javax, jakarta = checkJarNamespaces(root, "/app/missing.jar")
+	assert.False(t, javax)
+	assert.False(t, jakarta)
+}
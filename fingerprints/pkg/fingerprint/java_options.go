@@ -0,0 +1,117 @@
+package fingerprint
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// Garbage collectors selected by -XX:+Use<name>GC options
+var garbageCollectors = map[string]string{
+	"-XX:+UseSerialGC":        "Serial",
+	"-XX:+UseParallelGC":      "Parallel",
+	"-XX:+UseParallelOldGC":   "Parallel",
+	"-XX:+UseConcMarkSweepGC": "CMS",
+	"-XX:+UseG1GC":            "G1",
+	"-XX:+UseZGC":             "ZGC",
+	"-XX:+UseShenandoahGC":    "Shenandoah",
+	"-XX:+UseEpsilonGC":       "Epsilon",
+}
+
+// Options of the java launcher whose value is the next argument
+var optionsWithValue = []string{
+	"-cp", "-classpath", "--class-path",
+	"-p", "--module-path", "--upgrade-module-path",
+	"--add-modules", "--add-opens", "--add-exports", "--add-reads",
+	"--limit-modules", "--patch-module", "--enable-native-access",
+}
+
+// JavaOptions fingerprints the resource configuration of the JVM from its options
+type JavaOptions struct{}
+
+func init() {
+	Register(&JavaOptions{})
+}
+
+func (*JavaOptions) Name() string {
+	return "java-options"
+}
+
+func (*JavaOptions) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	javaToolOptions := process.Env("JAVA_TOOL_OPTIONS")
+	jdkJavaOptions := process.Env("JDK_JAVA_OPTIONS")
+	commandLine := process.CommandLine
+
+	// The JVM reads JAVA_TOOL_OPTIONS first, then the java launcher prepends JDK_JAVA_OPTIONS to the command line arguments.
+	// When an option is repeated, the last one wins.
+	// JAVA_OPTS is not read by the JVM: launcher scripts that use it expand it in the command line.
+	var javaOptions []string
+	if len(commandLine) > 0 {
+		javaOptions = slices.Concat(strings.Fields(javaToolOptions), strings.Fields(jdkJavaOptions), commandLine[1:])
+	}
+
+	entries := make(map[string]string)
+	maxHeap := ""
+	for i := 0; i < len(javaOptions); i++ {
+		option := javaOptions[i]
+		// the arguments that follow the main class, the main module or the executable jar are application arguments
+		if option == "-jar" || option == "-m" || option == "--module" || !strings.HasPrefix(option, "-") {
+			break
+		}
+		switch {
+		case slices.Contains(optionsWithValue, option):
+			i++
+		case strings.HasPrefix(option, "-Xmx"):
+			maxHeap = strings.TrimPrefix(option, "-Xmx")
+		case strings.HasPrefix(option, "-XX:MaxHeapSize="):
+			maxHeap = strings.TrimPrefix(option, "-XX:MaxHeapSize=")
+		case strings.HasPrefix(option, "-XX:MaxRAMPercentage="):
+			entries["java-max-ram-percentage"] = strings.TrimPrefix(option, "-XX:MaxRAMPercentage=")
+		case strings.HasPrefix(option, "-XX:ActiveProcessorCount="):
+			entries["java-active-processor-count"] = strings.TrimPrefix(option, "-XX:ActiveProcessorCount=")
+		case option == "-XX:-UseContainerSupport":
+			entries["java-container-support"] = "false"
+		case option == "-XX:+UseContainerSupport":
+			entries["java-container-support"] = "true"
+		default:
+			if gc, found := garbageCollectors[option]; found {
+				entries["java-gc"] = gc
+			}
+		}
+	}
+
+	if maxHeap != "" {
+		entries["java-max-heap"] = maxHeap
+	}
+
+	evidence := []Evidence{{Type: EvidenceCommandLine}}
+	if javaToolOptions != "" {
+		evidence = append(evidence, Evidence{Type: EvidenceEnvironment, Detail: "JAVA_TOOL_OPTIONS"})
+	}
+	if jdkJavaOptions != "" {
+		evidence = append(evidence, Evidence{Type: EvidenceEnvironment, Detail: "JDK_JAVA_OPTIONS"})
+	}
+	// the memory limit is only known for a running container (not for an unpacked root filesystem)
+	if ctx.Root == "" {
+		if memoryLimit, exists := utils.GetContainerMemoryLimit("/sys/fs/cgroup"); exists {
+			evidence = append(evidence, Evidence{Type: EvidenceFile, Path: "/sys/fs/cgroup"})
+			addMemoryLimitEntries(entries, maxHeap, memoryLimit)
+		}
+	}
+
+	return ctx.Write("java-options", entries, ConfidenceHigh, evidence...)
+}
+
+// addMemoryLimitEntries adds the memory limit of the container and whether the max heap exceeds it
+func addMemoryLimitEntries(entries map[string]string, maxHeap string, memoryLimit int64) {
+	entries["container-memory-limit"] = strconv.FormatInt(memoryLimit, 10)
+	if maxHeapBytes, err := utils.ParseJavaMemorySize(maxHeap); err == nil {
+		entries["java-max-heap-exceeds-memory-limit"] = strconv.FormatBool(maxHeapBytes > memoryLimit)
+	}
+}
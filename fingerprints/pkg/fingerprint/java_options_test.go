@@ -0,0 +1,104 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJavaOptions(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		environ     map[string]string
+		commandLine []string
+		values      map[string]string
+		envVars     []string
+	}{
+		{
+			name:        "max heap",
+			commandLine: []string{"java", "-Xms256m", "-Xmx512m", "-jar", "app.jar"},
+			values:      map[string]string{"java-max-heap": "512m"},
+		},
+		{
+			name:        "max heap size",
+			commandLine: []string{"java", "-XX:MaxHeapSize=1g", "-cp", "app.jar", "com.acme.Main"},
+			values:      map[string]string{"java-max-heap": "1g"},
+		},
+		{
+			name:        "max RAM percentage and GC",
+			commandLine: []string{"java", "-XX:MaxRAMPercentage=75.0", "-XX:+UseG1GC", "-XX:ActiveProcessorCount=2", "-XX:-UseContainerSupport", "-jar", "app.jar"},
+			values: map[string]string{"java-max-ram-percentage": "75.0", "java-gc": "G1", "java-active-processor-count": "2",
+				"java-container-support": "false"},
+		},
+		{
+			// the JVM reads JAVA_TOOL_OPTIONS first, then the launcher prepends JDK_JAVA_OPTIONS to the arguments
+			name:        "environment variables",
+			environ:     map[string]string{"JAVA_TOOL_OPTIONS": "-Xmx256m -XX:+UseSerialGC", "JDK_JAVA_OPTIONS": "-Xmx1g -XX:MaxRAMPercentage=50"},
+			commandLine: []string{"java", "-jar", "app.jar"},
+			values:      map[string]string{"java-max-heap": "1g", "java-gc": "Serial", "java-max-ram-percentage": "50"},
+			envVars:     []string{"JAVA_TOOL_OPTIONS", "JDK_JAVA_OPTIONS"},
+		},
+		{
+			// the options of the command line come last and win
+			name:        "command line after JDK_JAVA_OPTIONS",
+			environ:     map[string]string{"JDK_JAVA_OPTIONS": "-Xmx1g -XX:+UseParallelGC"},
+			commandLine: []string{"java", "-Xmx2g", "-XX:+UseZGC", "com.acme.Main"},
+			values:      map[string]string{"java-max-heap": "2g", "java-gc": "ZGC"},
+			envVars:     []string{"JDK_JAVA_OPTIONS"},
+		},
+		{
+			// the application arguments are not options of the JVM
+			name:        "application arguments",
+			commandLine: []string{"java", "-cp", "app.jar", "com.acme.Main", "-Xmx4g", "-XX:+UseZGC"},
+			values:      map[string]string{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			writeProcess(t, outputDir, "/app", test.environ, test.commandLine...)
+
+			// the memory limit is not read from an unpacked root filesystem
+			assert.NoError(t, Run("java-options", []string{"--root", t.TempDir(), outputDir}))
+			files, _ := filepath.Glob(filepath.Join(outputDir, "java-options.java-options.*.json"))
+			if assert.Len(t, files, 1) {
+				content, err := os.ReadFile(files[0])
+				assert.NoError(t, err)
+				var result Result
+				assert.NoError(t, json.Unmarshal(content, &result))
+				assert.Equal(t, test.values, result.Values)
+				var envVars []string
+				for _, evidence := range result.Evidence {
+					if evidence.Type == EvidenceEnvironment {
+						envVars = append(envVars, evidence.Detail)
+					}
+				}
+				assert.Equal(t, test.envVars, envVars)
+			}
+		})
+	}
+}
+
+func TestAddMemoryLimitEntries(t *testing.T) {
+	for _, test := range []struct {
+		maxHeap     string
+		memoryLimit int64
+		entries     map[string]string
+	}{
+		{maxHeap: "512m", memoryLimit: 1024 * 1024 * 1024,
+			entries: map[string]string{"container-memory-limit": "1073741824", "java-max-heap-exceeds-memory-limit": "false"}},
+		{maxHeap: "2G", memoryLimit: 1024 * 1024 * 1024,
+			entries: map[string]string{"container-memory-limit": "1073741824", "java-max-heap-exceeds-memory-limit": "true"}},
+		{maxHeap: "1g", memoryLimit: 1024 * 1024 * 1024,
+			entries: map[string]string{"container-memory-limit": "1073741824", "java-max-heap-exceeds-memory-limit": "false"}},
+		// without max heap, the JVM sizes the heap from the memory limit
+		{maxHeap: "", memoryLimit: 1024 * 1024 * 1024,
+			entries: map[string]string{"container-memory-limit": "1073741824"}},
+	} {
+		entries := map[string]string{}
+		addMemoryLimitEntries(entries, test.maxHeap, test.memoryLimit)
+		assert.Equal(t, test.entries, entries, test.maxHeap)
+	}
+}
@@ -0,0 +1,193 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// JavaRuntimes fingerprints the Java runtimes (frameworks, application servers, JVM languages) of the application
+type JavaRuntimes struct{}
+
+func init() {
+	Register(&JavaRuntimes{})
+}
+
+func (*JavaRuntimes) Name() string {
+	return "java-runtimes"
+}
+
+func (*JavaRuntimes) Fingerprint(ctx *Context) error {
+	application, found, err := getJavaApplication(ctx)
+	if err != nil || !found {
+		return err
+	}
+	// the jar can be empty if the application is not run from a jar
+	inspectedJar := application.jar
+	classpath := application.classpath
+	// the main class of a classpath-based application
+	mainClass := application.mainClass
+
+	log.Printf("🔎 Fingerprinting the Java runtimes from %s (classpath: %s)\n", inspectedJar, classpath)
+
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+	javaConfigs := config.Fingerprints.Java
+
+	entries := make(map[string]string)
+	evidence := []Evidence{}
+
+	// the main attributes of the manifest of the executable jar
+	manifestEntries := utils.Attributes{}
+	if inspectedJar != "" {
+		manifest, err := utils.GetJarManifest(ctx.Root, inspectedJar)
+		if err != nil {
+			return fmt.Errorf("unable to read manifest entries from %s: %w", inspectedJar, err)
+		}
+		manifestEntries = manifest.Main
+	}
+	if mainClass, found := manifestEntries.Lookup("Main-Class"); found {
+		// find the config matching this main class
+		idx := slices.IndexFunc(javaConfigs, func(jc utils.JavaRuntimeExecutables) bool { return jc.MainClass == mainClass })
+		if idx >= 0 {
+			javaConfig := javaConfigs[idx]
+			log.Printf("Found fingerprint configuration for java main-class %s: %+v\n", mainClass, javaConfig)
+			evidence = append(evidence, Evidence{Type: EvidenceManifestEntry, Path: inspectedJar, Detail: "Main-Class"})
+			if javaConfig.ReadManifestOfExecutableJar {
+				entries[javaConfig.RuntimeName] = manifestEntries.Get(javaConfig.JarVersionManifestEntry)
+				evidence = append(evidence, Evidence{Type: EvidenceManifestEntry, Path: inspectedJar, Detail: javaConfig.JarVersionManifestEntry})
+			} else {
+				log.Printf("Read version for another class\n")
+				// find the jars that contains the main class
+				classPath := manifestEntries.Get("Class-Path")
+				log.Printf("Classpath = %s\n", classPath)
+				for _, otherJar := range strings.Fields(classPath) {
+					if !filepath.IsAbs(otherJar) {
+						// Get the absolute path of the parent directory
+						parentDir := filepath.Dir(inspectedJar)
+						otherJar = filepath.Join(parentDir, otherJar)
+					}
+					if utils.JarFileContainsClass(ctx.Root, otherJar, mainClass) {
+						otherManifest, err := utils.GetJarManifest(ctx.Root, otherJar)
+						if err != nil {
+							return fmt.Errorf("unable to read manifest entries from %s: %w", otherJar, err)
+						}
+						entries[javaConfig.RuntimeName] = otherManifest.Main.Get(javaConfig.JarVersionManifestEntry)
+						evidence = append(evidence, Evidence{Type: EvidenceManifestEntry, Path: otherJar, Detail: javaConfig.JarVersionManifestEntry})
+					}
+				}
+			}
+		}
+	}
+
+	if len(entries) > 0 {
+		if err := ctx.Write("java-runtimes-fingerprints", entries, ConfidenceHigh, evidence...); err != nil {
+			return err
+		}
+	}
+
+	classpathJars := utils.GetClasspathJars(ctx.Root, inspectedJar, classpath)
+	if languageEntries, languageEvidence := getJvmLanguages(ctx.Root, config.Fingerprints.JvmLanguages, classpathJars); len(languageEntries) > 0 {
+		if err := ctx.Write("jvm-languages-fingerprints", languageEntries, ConfidenceHigh, languageEvidence...); err != nil {
+			return err
+		}
+	}
+
+	if majorVersion, classEvidence, err := getApplicationClassMajorVersion(ctx.Root, inspectedJar, manifestEntries, classpath, classpathJars, mainClass); err == nil {
+		log.Printf("Application main class has been compiled with class file major version %d\n", majorVersion)
+		bytecodeEntries := make(map[string]string)
+		bytecodeEntries["java-bytecode-version"] = utils.JavaReleaseFromClassMajorVersion(majorVersion)
+		return ctx.Write("java-bytecode", bytecodeEntries, ConfidenceHigh, classEvidence)
+	}
+	return nil
+}
+
+// getApplicationClassMajorVersion returns the class file major version of the application's main class
+// and the location of its class file.
+//
+// For Spring Boot applications, the application's main class is the `Start-Class` manifest entry of the executable jar
+// (the `Main-Class` being the Spring Boot launcher).
+func getApplicationClassMajorVersion(root string, inspectedJar string, manifestEntries utils.Attributes, classpath string, classpathJars []string, mainClass string) (uint16, Evidence, error) {
+	if startClass, ok := manifestEntries.Lookup("Start-Class"); ok {
+		classFile := "BOOT-INF/classes/" + utils.ClassFileName(startClass)
+		majorVersion, err := utils.GetJarClassMajorVersion(root, inspectedJar, classFile)
+		return majorVersion, Evidence{Type: EvidenceJarEntry, Path: inspectedJar, Detail: classFile}, err
+	}
+	if mainClass == "" {
+		mainClass = manifestEntries.Get("Main-Class")
+	}
+	if mainClass == "" {
+		return 0, Evidence{}, fmt.Errorf("main class of the application is not known")
+	}
+	classFile := utils.ClassFileName(mainClass)
+	for _, jar := range classpathJars {
+		if majorVersion, err := utils.GetJarClassMajorVersion(root, jar, classFile); err == nil {
+			return majorVersion, Evidence{Type: EvidenceJarEntry, Path: jar, Detail: classFile}, nil
+		}
+	}
+	// the main class can also be in a directory of the classpath
+	for _, entry := range strings.Split(classpath, string(os.PathListSeparator)) {
+		if entry == "" || strings.HasSuffix(entry, ".jar") || strings.HasSuffix(entry, "*") {
+			continue
+		}
+		path := filepath.Join(entry, classFile)
+		if file, err := utils.Open(root, path); err == nil {
+			defer file.Close()
+			majorVersion, err := utils.ReadClassMajorVersion(file)
+			return majorVersion, Evidence{Type: EvidenceFile, Path: path}, err
+		}
+	}
+	return 0, Evidence{}, fmt.Errorf("class file for %s not found", mainClass)
+}
+
+// getJvmLanguages returns the JVM languages (and their versions) whose standard library is on the classpath
+// or nested in one of its jars, and the jars of these standard libraries
+func getJvmLanguages(root string, languages []utils.JvmLanguage, classpathJars []string) (map[string]string, []Evidence) {
+	entries := make(map[string]string)
+	evidence := []Evidence{}
+
+	nestedJars := make(map[string][]string)
+	for _, jar := range classpathJars {
+		nestedJars[jar] = utils.ListNestedJars(root, jar)
+	}
+
+	for _, language := range languages {
+	jarNames:
+		for _, jarName := range language.JarNames {
+			for _, jar := range classpathJars {
+				if version, found := utils.MatchJarVersion(jar, jarName); found {
+					if version == "" {
+						if manifest, err := utils.GetJarManifest(root, jar); err == nil {
+							version = manifest.Main.Get("Implementation-Version")
+						}
+					}
+					entries[language.RuntimeName] = version
+					evidence = append(evidence, Evidence{Type: EvidenceFile, Path: jar})
+					break jarNames
+				}
+				for _, nestedJar := range nestedJars[jar] {
+					if version, found := utils.MatchJarVersion(nestedJar, jarName); found {
+						if version == "" {
+							if r, err := utils.OpenNestedJar(root, jar, nestedJar); err == nil {
+								if manifest, err := utils.ReadJarManifest(r); err == nil {
+									version = manifest.Main.Get("Implementation-Version")
+								}
+							}
+						}
+						entries[language.RuntimeName] = version
+						evidence = append(evidence, Evidence{Type: EvidenceJarEntry, Path: jar, Detail: nestedJar})
+						break jarNames
+					}
+				}
+			}
+		}
+	}
+	return entries, evidence
+}
@@ -0,0 +1,155 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// JavaVersion fingerprints the version and the profile of the Java runtime image
+type JavaVersion struct{}
+
+func init() {
+	Register(&JavaVersion{})
+}
+
+func (*JavaVersion) Name() string {
+	return "java-version"
+}
+
+func (*JavaVersion) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	javaHomeDir := process.Env("JAVA_HOME")
+	if javaHomeDir == "" {
+		// find the java home directory based on the location of the java executable
+		// ($JAVA_HOME/bin/java)
+		javaExecutable, err := utils.FindExecutableInPath(ctx.Root, "java", process.Env("PATH"))
+		if err != nil {
+			return fmt.Errorf("unable to find java home directory: %w", err)
+		}
+		javaHomeDir = getJavaHome(ctx.Root, javaExecutable, "")
+	}
+	log.Printf("🔎 Fingerprinting the Java version from %s\n", javaHomeDir)
+
+	entries := make(map[string]string)
+	// read the release file from the $JAVA_HOME directory
+	entries["runtime-kind"] = "Java"
+	releaseFile := filepath.Join(javaHomeDir, "release")
+	properties, exists := utils.ReadPropertiesFile(ctx.Root, releaseFile)
+	// without release file, only the runtime kind is known
+	if !exists {
+		return ctx.Write("runtime-kind", entries, ConfidenceMedium, Evidence{Type: EvidenceFile, Path: filepath.Join(javaHomeDir, "bin", "java")})
+	}
+	for k, v := range properties {
+		switch k {
+		case "JAVA_VERSION":
+			entries["runtime-kind-version"] = v
+		case "IMPLEMENTOR":
+			entries["runtime-kind-implementer"] = v
+		}
+	}
+
+	// fingerprint the profile of the Java runtime image
+	if jvmVariant := getJvmVariant(properties); jvmVariant != "" {
+		entries["java-jvm-variant"] = jvmVariant
+	}
+	entries["java-image-type"] = getImageType(ctx.Root, javaHomeDir, properties)
+	entries["java-cds-archive"] = strconv.FormatBool(hasCDSArchive(ctx.Root, javaHomeDir))
+	if vendorVersion := getVendorVersion(properties); vendorVersion != "" {
+		entries["java-vendor-version"] = vendorVersion
+	}
+	return ctx.Write("runtime-kind", entries, ConfidenceHigh, Evidence{Type: EvidenceFile, Path: releaseFile, Detail: "JAVA_VERSION"})
+}
+
+// getJavaHome returns the Java home directory if the process is a java process
+func getJavaHome(root string, executable string, javaHomeEnvVar string) string {
+	if filepath.Base(executable) != "java" {
+		return ""
+	}
+	if javaHomeEnvVar != "" {
+		return javaHomeEnvVar
+	}
+	// $JAVA_HOME/bin/java
+	if resolved, err := utils.EvalSymlinks(root, executable); err == nil {
+		executable = resolved
+	}
+	return filepath.Dir(filepath.Dir(executable))
+}
+
+// getJvmVariant returns the JVM implementation (HotSpot or OpenJ9) of the Java runtime
+// based on the JVM_VARIANT field of the release file.
+// If that field is absent, the IMPLEMENTOR_VERSION field is checked to detect OpenJ9 builds.
+func getJvmVariant(properties map[string]string) string {
+	variant := strings.ToLower(properties["JVM_VARIANT"])
+	switch {
+	case variant == "hotspot":
+		return "HotSpot"
+	case variant == "openj9":
+		return "OpenJ9"
+	case variant != "":
+		return properties["JVM_VARIANT"]
+	case strings.Contains(strings.ToLower(properties["IMPLEMENTOR_VERSION"]), "openj9"):
+		return "OpenJ9"
+	}
+	return ""
+}
+
+// getImageType returns whether the Java runtime is a full JDK, a JRE or a custom jlink image.
+//
+// The MODULES field of the release file lists the modules that are linked in the runtime image:
+// - a JDK contains the jdk.compiler module (javac)
+// - a JRE contains all the Java SE modules (the java.se aggregator module) without the compiler
+// - any other list of modules corresponds to a custom runtime created by jlink
+//
+// Runtimes that do not use modules (Java 8) are identified by the presence of the javac executable.
+func getImageType(root string, javaHomeDir string, properties map[string]string) string {
+	if modulesList, ok := properties["MODULES"]; ok {
+		modules := strings.Fields(modulesList)
+		switch {
+		case slices.Contains(modules, "jdk.compiler"):
+			return "JDK"
+		case slices.Contains(modules, "java.se"):
+			return "JRE"
+		default:
+			return "jlink"
+		}
+	}
+	if _, err := utils.Stat(root, filepath.Join(javaHomeDir, "bin", "javac")); err == nil {
+		return "JDK"
+	}
+	return "JRE"
+}
+
+// hasCDSArchive returns true if the Java runtime contains a default Class Data Sharing archive
+func hasCDSArchive(root string, javaHomeDir string) bool {
+	patterns := []string{
+		// Java 9+
+		filepath.Join(javaHomeDir, "lib", "server", "classes*.jsa"),
+		// Java 8 JDK & JRE
+		filepath.Join(javaHomeDir, "jre", "lib", "*", "server", "classes*.jsa"),
+		filepath.Join(javaHomeDir, "lib", "*", "server", "classes*.jsa"),
+	}
+	for _, pattern := range patterns {
+		if matches := utils.Glob(root, pattern); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getVendorVersion returns the build version of the Java runtime as set by its vendor
+// (for example "Temurin-21.0.3+9" or "Red_Hat-17.0.11.0.9-1")
+func getVendorVersion(properties map[string]string) string {
+	if vendorVersion := properties["IMPLEMENTOR_VERSION"]; vendorVersion != "" {
+		return vendorVersion
+	}
+	return properties["JAVA_RUNTIME_VERSION"]
+}
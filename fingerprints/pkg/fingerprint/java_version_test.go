@@ -0,0 +1,124 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetJvmVariant(t *testing.T) {
+	for _, test := range []struct {
+		properties map[string]string
+		variant    string
+	}{
+		{properties: map[string]string{"JVM_VARIANT": "Hotspot"}, variant: "HotSpot"},
+		{properties: map[string]string{"JVM_VARIANT": "openj9"}, variant: "OpenJ9"},
+		{properties: map[string]string{"JVM_VARIANT": "GraalVM"}, variant: "GraalVM"},
+		// IBM Semeru builds without JVM_VARIANT
+		{properties: map[string]string{"IMPLEMENTOR_VERSION": "IBM Semeru Runtime Open Edition (OpenJ9)"}, variant: "OpenJ9"},
+		{properties: map[string]string{"IMPLEMENTOR_VERSION": "Temurin-21.0.3+9"}, variant: ""},
+		{properties: nil, variant: ""},
+	} {
+		assert.Equal(t, test.variant, getJvmVariant(test.properties), test.properties)
+	}
+}
+
+func TestGetImageType(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/usr/lib/jvm/java-1.8.0/bin/javac", "")
+	writeRootFile(t, root, "/usr/lib/jvm/jre-1.8.0/bin/java", "")
+
+	for _, test := range []struct {
+		javaHomeDir string
+		properties  map[string]string
+		imageType   string
+	}{
+		{javaHomeDir: "/opt/java", properties: map[string]string{"MODULES": "java.base java.compiler java.se jdk.compiler jdk.jshell"}, imageType: "JDK"},
+		{javaHomeDir: "/opt/java", properties: map[string]string{"MODULES": "java.base java.logging java.se jdk.unsupported"}, imageType: "JRE"},
+		{javaHomeDir: "/opt/java", properties: map[string]string{"MODULES": "java.base java.logging java.naming"}, imageType: "jlink"},
+		// Java 8 runtimes have no MODULES field
+		{javaHomeDir: "/usr/lib/jvm/java-1.8.0", properties: map[string]string{"JAVA_VERSION": "1.8.0_412"}, imageType: "JDK"},
+		{javaHomeDir: "/usr/lib/jvm/jre-1.8.0", properties: map[string]string{"JAVA_VERSION": "1.8.0_412"}, imageType: "JRE"},
+	} {
+		assert.Equal(t, test.imageType, getImageType(root, test.javaHomeDir, test.properties), test.properties)
+	}
+}
+
+func TestHasCDSArchive(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/opt/java/21/lib/server/classes.jsa", "")
+	writeRootFile(t, root, "/opt/java/17/lib/server/classes_nocoops.jsa", "")
+	writeRootFile(t, root, "/usr/lib/jvm/java-1.8.0/jre/lib/amd64/server/classes.jsa", "")
+	writeRootFile(t, root, "/usr/lib/jvm/jre-1.8.0/lib/amd64/server/classes.jsa", "")
+	writeRootFile(t, root, "/opt/java/jlink/lib/server/libjvm.so", "")
+
+	for _, test := range []struct {
+		javaHomeDir string
+		found       bool
+	}{
+		{javaHomeDir: "/opt/java/21", found: true},
+		{javaHomeDir: "/opt/java/17", found: true},
+		{javaHomeDir: "/usr/lib/jvm/java-1.8.0", found: true},
+		{javaHomeDir: "/usr/lib/jvm/jre-1.8.0", found: true},
+		{javaHomeDir: "/opt/java/jlink", found: false},
+	} {
+		assert.Equal(t, test.found, hasCDSArchive(root, test.javaHomeDir), test.javaHomeDir)
+	}
+}
+
+func TestGetVendorVersion(t *testing.T) {
+	assert.Equal(t, "Temurin-21.0.3+9", getVendorVersion(map[string]string{"IMPLEMENTOR_VERSION": "Temurin-21.0.3+9", "JAVA_RUNTIME_VERSION": "21.0.3+9-LTS"}))
+	assert.Equal(t, "17.0.11+9-LTS", getVendorVersion(map[string]string{"JAVA_RUNTIME_VERSION": "17.0.11+9-LTS"}))
+	assert.Equal(t, "", getVendorVersion(map[string]string{"JAVA_VERSION": "1.8.0_412"}))
+}
+
+func TestJavaVersion(t *testing.T) {
+	for _, test := range []struct {
+		name       string
+		files      map[string]string
+		values     map[string]string
+		confidence Confidence
+	}{
+		{
+			name: "release file",
+			files: map[string]string{
+				"/usr/lib/jvm/jre/release":                "JAVA_VERSION=\"17.0.11\"\nIMPLEMENTOR=\"Red Hat, Inc.\"\nMODULES=\"java.base java.logging\"\n",
+				"/usr/lib/jvm/jre/lib/server/classes.jsa": "",
+				"/usr/lib/jvm/jre/bin/java":               "",
+			},
+			values: map[string]string{"runtime-kind": "Java", "runtime-kind-version": "17.0.11", "runtime-kind-implementer": "Red Hat, Inc.",
+				"java-image-type": "jlink", "java-cds-archive": "true"},
+			confidence: ConfidenceHigh,
+		},
+		{
+			// the profile of the runtime image is unknown without release file
+			name:       "no release file",
+			files:      map[string]string{"/usr/lib/jvm/jre/bin/java": ""},
+			values:     map[string]string{"runtime-kind": "Java"},
+			confidence: ConfidenceMedium,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, content := range test.files {
+				writeRootFile(t, root, path, content)
+			}
+			outputDir := t.TempDir()
+			writeProcess(t, outputDir, "/", map[string]string{"JAVA_HOME": "/usr/lib/jvm/jre"}, "/usr/lib/jvm/jre/bin/java", "-jar", "app.jar")
+
+			assert.NoError(t, Run("java-version", []string{"--root", root, outputDir}))
+			files, _ := filepath.Glob(filepath.Join(outputDir, "runtime-kind.java-version.*.json"))
+			if assert.Len(t, files, 1) {
+				content, err := os.ReadFile(files[0])
+				assert.NoError(t, err)
+				var result Result
+				assert.NoError(t, json.Unmarshal(content, &result))
+				assert.Equal(t, test.values, result.Values)
+				assert.Equal(t, test.confidence, result.Confidence)
+			}
+		})
+	}
+}
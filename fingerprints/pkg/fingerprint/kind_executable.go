@@ -0,0 +1,123 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// KindExecutable fingerprints the version of the runtime from the output of its executable `--version` option
+// (or of the arguments of its version-executables configuration).
+// The version and the implementer are extracted from the output with the regexes of the configuration.
+//
+// In the static version detection mode (and for an unpacked root filesystem whose executables are not trusted),
+// the executable is not run and the version is read from the files of the runtime.
+type KindExecutable struct{}
+
+func init() {
+	Register(&KindExecutable{})
+}
+
+func (*KindExecutable) Name() string {
+	return "kind-executable"
+}
+
+func (*KindExecutable) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+	// the runtime kind is the name of the runtime-kind of the version-executables configuration that matches the process
+	idx := slices.IndexFunc(config.Fingerprints.VersionExecutables, func(ve utils.VersionExecutable) bool {
+		return slices.Contains(ve.ProcessNames, process.Name)
+	})
+	if idx < 0 {
+		return nil
+	}
+	versionExecutable := config.Fingerprints.VersionExecutables[idx]
+	executable := process.Executable()
+	log.Printf("🔎 Fingerprinting the version-able executable %s\n", executable)
+
+	// the executables of an unpacked root filesystem are not trusted (and may not run on the host)
+	if ctx.Root != "" || config.Fingerprints.VersionDetection == utils.VersionDetectionStatic {
+		// the PATH of the process finds the executable when it is not a path
+		return fingerprintStaticVersion(ctx, executable, versionExecutable, process.Env("PATH"))
+	}
+
+	versionOutput, err := utils.GetExecutableVersionOutput(ctx.Root, executable, versionExecutable)
+	if err != nil {
+		return fmt.Errorf("unable to get the version of %s: %w", executable, err)
+	}
+	entries := map[string]string{
+		"runtime-kind": versionExecutable.RuntimeKindName,
+	}
+	// the output of `--version` has no defined format
+	confidence := parseVersionOutput(versionExecutable, versionOutput, entries, ConfidenceMedium)
+	return ctx.Write("runtime-kind", entries, confidence,
+		Evidence{Type: EvidenceCommandOutput, Path: executable, Detail: strings.Join(versionExecutable.VersionArguments(), " ")})
+}
+
+// parseVersionOutput sets the runtime-kind-version and runtime-kind-implementer entries from the output of the version-executable
+// and returns the confidence of the values.
+// The version is the trimmed output if the executable has no version-regex, and the confidence is low if its version-regex does not match.
+func parseVersionOutput(versionExecutable utils.VersionExecutable, output string, entries map[string]string, confidence Confidence) Confidence {
+	entries["runtime-kind-version"] = strings.TrimSpace(output)
+	if versionExecutable.CompiledVersionRegex != nil {
+		if version, found := matchNamedGroup(versionExecutable.CompiledVersionRegex, "version", output); found {
+			entries["runtime-kind-version"] = version
+		} else {
+			confidence = ConfidenceLow
+		}
+	}
+	if versionExecutable.CompiledImplementerRegex != nil {
+		if implementer, found := matchNamedGroup(versionExecutable.CompiledImplementerRegex, "implementer", output); found {
+			entries["runtime-kind-implementer"] = implementer
+		}
+	}
+	return confidence
+}
+
+// matchNamedGroup returns the value of the named group of the first match of the regex in s.
+// The regexes of the configuration are compiled with their named group (see utils.GetConfig).
+func matchNamedGroup(re *regexp.Regexp, group string, s string) (string, bool) {
+	matches := re.FindStringSubmatch(s)
+	if matches == nil {
+		return "", false
+	}
+	return strings.TrimSpace(matches[re.SubexpIndex(group)]), true
+}
+
+// fingerprintStaticVersion fingerprints the version of the runtime without running its executable
+// The static version is parsed like the output of the executable.
+func fingerprintStaticVersion(ctx *Context, executable string, versionExecutable utils.VersionExecutable, pathEnvVar string) error {
+	if !strings.Contains(executable, "/") && pathEnvVar != "" {
+		if path, err := utils.FindExecutableInPath(ctx.Root, executable, pathEnvVar); err == nil {
+			executable = path
+		}
+	}
+	runtimeKind := versionExecutable.RuntimeKindName
+	entries := map[string]string{
+		"runtime-kind": runtimeKind,
+	}
+
+	detect, supported := staticVersionDetectors[runtimeKind]
+	if !supported {
+		log.Printf("No static version detection for the runtime kind %s\n", runtimeKind)
+		// the runtime kind is only known from the name of the process
+		return ctx.Write("runtime-kind", entries, ConfidenceLow)
+	}
+	detected, found := detect(ctx.Root, executable)
+	if !found {
+		return fmt.Errorf("unable to read the version of %s from its files", executable)
+	}
+	confidence := parseVersionOutput(versionExecutable, detected.version, entries, detected.confidence)
+	return ctx.Write("runtime-kind", entries, confidence, detected.evidence)
+}
@@ -0,0 +1,55 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"fingerprints/pkg/utils"
+)
+
+func TestParseVersionOutput(t *testing.T) {
+	entries := map[string]string{}
+	confidence := parseVersionOutput(utils.VersionExecutable{}, "v18.19.1\n", entries, ConfidenceMedium)
+	assert.Equal(t, ConfidenceMedium, confidence)
+	assert.Equal(t, map[string]string{"runtime-kind-version": "v18.19.1"}, entries)
+
+	entries = map[string]string{}
+	confidence = parseVersionOutput(utils.VersionExecutable{
+		CompiledVersionRegex:     regexp.MustCompile(`version "(?P<version>[^"]+)"`),
+		CompiledImplementerRegex: regexp.MustCompile(`(?m)^(?P<implementer>\S+) Runtime Environment`),
+	}, "openjdk version \"17.0.11\" 2024-04-16 LTS\nOpenJDK Runtime Environment (Red_Hat-17.0.11.0.9-1) (build 17.0.11+9-LTS)\n", entries, ConfidenceMedium)
+	assert.Equal(t, ConfidenceMedium, confidence)
+	assert.Equal(t, map[string]string{"runtime-kind-version": "17.0.11", "runtime-kind-implementer": "OpenJDK"}, entries)
+
+	// the output is kept (with a low confidence) when the version-regex does not match
+	entries = map[string]string{}
+	confidence = parseVersionOutput(utils.VersionExecutable{CompiledVersionRegex: regexp.MustCompile(`^v(?P<version>\S+)`)}, "Python 3.12.2\n", entries, ConfidenceMedium)
+	assert.Equal(t, ConfidenceLow, confidence)
+	assert.Equal(t, map[string]string{"runtime-kind-version": "Python 3.12.2"}, entries)
+}
+
+func TestKindExecutable(t *testing.T) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "nginx")
+	writeRootFile(t, dir, "nginx", "#!/bin/sh\necho \"nginx version: nginx/1.24.0\" >&2\n")
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", "[[fingerprints.version-executables]]\nprocess-names = [\"nginx\"]\nruntime-kind-name = \"nginx\"\n"+
+		"arguments = [\"-v\"]\ncapture = \"stderr\"\nversion-regex = 'nginx/(?P<version>[0-9.]+)'\ntimeout = \"5s\"\n")
+	writeProcess(t, outputDir, "/", nil, executable, "-g", "daemon off;")
+
+	assert.NoError(t, Run("kind-executable", []string{outputDir}))
+	files, _ := filepath.Glob(filepath.Join(outputDir, "runtime-kind.kind-executable.*.json"))
+	assert.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	var result Result
+	assert.NoError(t, json.Unmarshal(content, &result))
+	assert.Equal(t, map[string]string{"runtime-kind": "nginx", "runtime-kind-version": "1.24.0"}, result.Values)
+	assert.Equal(t, ConfidenceMedium, result.Confidence)
+	assert.Equal(t, []Evidence{{Type: EvidenceCommandOutput, Path: executable, Detail: "-v"}}, result.Evidence)
+}
@@ -1,4 +1,4 @@
-package main
+package fingerprint
 
 import (
 	"bufio"
@@ -9,86 +9,90 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/saferwall/elf"
 
 	"fingerprints/pkg/utils"
 )
 
-func main() {
-	// The program has parameters:
-	// - 1 - the subdirectory to write the manifest to
-	// - 2 - the executable current working directory
-	// - 2 - the name of the executable
-	outputDir := os.Args[1]
-	cwd := os.Args[2]
-	executable := os.Args[3]
+// NativeExecutable fingerprints the runtime of native executables (Go and GraalVM native images)
+type NativeExecutable struct{}
 
-	startTime := time.Now()
+func init() {
+	Register(&NativeExecutable{})
+}
+
+func (*NativeExecutable) Name() string {
+	return "native-executable"
+}
 
-	path := executable
-	if !strings.HasPrefix(executable, "/") {
-		path = filepath.Join(cwd, executable)
+func (*NativeExecutable) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	path := process.Executable()
+	if !strings.HasPrefix(path, "/") {
+		path = filepath.Join(process.Cwd, path)
 	}
 
-	log.Printf("🔎 Fingerprinting the native executable %s to %s\n", path, outputDir)
+	log.Printf("🔎 Fingerprinting the native executable %s\n", path)
 
-	isElf := isElfExecutable(path)
+	// the executable is read from the root filesystem (the evidence keeps the path of the container)
+	executable, err := utils.ResolvePath(ctx.Root, path)
+	if err != nil {
+		return err
+	}
+	isElf := isElfExecutable(executable)
 	if !isElf {
-		return
+		return nil
 	}
 
 	entries := make(map[string]string)
 
-	goVersion, err := getGoVersion(path)
+	goVersion, err := getGoVersion(executable)
 	if err == nil && goVersion != "" {
-
 		entries["runtime-kind"] = "Golang"
 		entries["runtime-kind-version"] = goVersion
-
-		utils.WriteEntries(outputDir, "runtime-kind.txt", entries)
-
-		endTime := time.Now()
-		duration := endTime.Sub(startTime)
-		log.Printf("Golang fingerprint executed in time: %s\n", duration)
-		return
+		return ctx.Write("runtime-kind", entries, ConfidenceHigh, Evidence{Type: EvidenceBuildInfo, Path: path, Detail: "GoVersion"})
 	}
 
 	// check whether the executable is a GraalVM executable
-	graalVMExec, err := checkGraalVMExecutable(path)
+	graalVMExec, err := checkGraalVMExecutable(executable)
 	if err != nil {
-		return
+		return err
+	}
+	if !graalVMExec {
+		return nil
 	}
 
-	if graalVMExec {
-		entries["runtime-kind"] = "GraalVM"
-		utils.WriteEntries(outputDir, "runtime-kind.txt", entries)
-
-		containsQuarkusStrings, err := checkQuarkusStrings(path)
-		if err != nil {
-			return
-		}
-		if containsQuarkusStrings {
-
-			runtimeEntries := make(map[string]string)
-			runtimeEntries["Quarkus"] = ""
-			utils.WriteEntries(outputDir, "quarkus-fingerprints.txt", runtimeEntries)
+	entries["runtime-kind"] = "GraalVM"
+	if err := ctx.Write("runtime-kind", entries, ConfidenceHigh, Evidence{Type: EvidenceELFSection, Path: path, Detail: ".svm_heap"}); err != nil {
+		return err
+	}
 
-		}
-		endTime := time.Now()
-		duration := endTime.Sub(startTime)
-		log.Printf("GraalVM/Quarkus fingerprint executed in time: %s\n", duration)
+	containsQuarkusStrings, err := checkQuarkusStrings(executable)
+	if err != nil {
+		return err
+	}
+	if containsQuarkusStrings {
+		runtimeEntries := make(map[string]string)
+		runtimeEntries["Quarkus"] = ""
+		return ctx.Write("quarkus-fingerprints", runtimeEntries, ConfidenceMedium, Evidence{Type: EvidenceExecutableStrings, Path: path, Detail: "quarkus.native"})
 	}
+	return nil
 }
 
 func checkQuarkusStrings(executable string) (bool, error) {
 	file, err := os.Open(executable)
 	if err != nil {
-		panic(err)
+		return false, err
 	}
 	defer file.Close()
-	found := get_strings(file, 14, 14, true)
+	found, err := get_strings(file, 14, 14, true)
+	if err != nil {
+		return false, err
+	}
 	for _, str := range found {
 		if strings.Contains(str, "quarkus.native") {
 			return true, nil
@@ -99,10 +103,10 @@ func checkQuarkusStrings(executable string) (bool, error) {
 
 func checkGraalVMExecutable(executable string) (bool, error) {
 	p, err := elf.New(executable)
-	defer p.CloseFile()
 	if err != nil {
 		return false, err
 	}
+	defer p.CloseFile()
 	err = p.Parse()
 	if err != nil {
 		return false, err
@@ -124,7 +128,7 @@ func getGoVersion(executable string) (string, error) {
 }
 
 // copied from https://github.com/robpike/strings/blob/master/strings.go
-func get_strings(file *os.File, min int, max int, ascii bool) []string {
+func get_strings(file *os.File, min int, max int, ascii bool) ([]string, error) {
 	in := bufio.NewReader(file)
 	str := make([]rune, 0, max)
 	found := make([]string, 1)
@@ -147,9 +151,9 @@ func get_strings(file *os.File, min int, max int, ascii bool) []string {
 			r, wid, err = in.ReadRune()
 			if err != nil {
 				if err != io.EOF {
-					panic(err)
+					return nil, err
 				}
-				return found
+				return found, nil
 			}
 			if !strconv.IsPrint(r) || ascii && r >= 0xFF {
 				add()
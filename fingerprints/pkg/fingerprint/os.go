@@ -0,0 +1,269 @@
+package fingerprint
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"fingerprints/pkg/ospackages"
+	"fingerprints/pkg/utils"
+)
+
+// fields of the os-release file and their corresponding entries
+var osReleaseFields = map[string]string{
+	"ID":               "os-release-id",
+	"VERSION_ID":       "os-release-version-id",
+	"ID_LIKE":          "os-release-id-like",
+	"PRETTY_NAME":      "os-release-pretty-name",
+	"VERSION_CODENAME": "os-release-version-codename",
+	"CPE_NAME":         "os-release-cpe-name",
+}
+
+// Names of the distributions in /etc/redhat-release and their os-release ID and ID_LIKE
+var redhatReleaseNames = []struct {
+	prefix string
+	id     string
+	idLike string
+}{
+	{"Red Hat Enterprise Linux", "rhel", "fedora"},
+	{"CentOS Stream", "centos", "rhel fedora"},
+	{"CentOS", "centos", "rhel fedora"},
+	{"Rocky Linux", "rocky", "rhel centos fedora"},
+	{"AlmaLinux", "almalinux", "rhel centos fedora"},
+	{"Oracle Linux", "ol", "fedora"},
+	{"Fedora", "fedora", ""},
+}
+
+var redhatReleaseVersion = regexp.MustCompile(`release ([0-9][0-9.]*)`)
+var busyboxVersion = regexp.MustCompile(`BusyBox v([0-9][0-9.]*)`)
+
+// Os fingerprints the Operating System of the container and the packages installed by its package manager
+type Os struct{}
+
+func init() {
+	Register(&Os{})
+}
+
+func (*Os) Name() string {
+	return "os"
+}
+
+func (*Os) Fingerprint(ctx *Context) error {
+	// the Operating System is identified from the first file that matches
+	detections := []struct {
+		path       string
+		confidence Confidence
+		detect     func(root string) (map[string]string, bool)
+	}{
+		{"/etc/os-release", ConfidenceHigh, func(root string) (map[string]string, bool) { return readOsRelease(root, "/etc/os-release") }},
+		{"/usr/lib/os-release", ConfidenceHigh, func(root string) (map[string]string, bool) { return readOsRelease(root, "/usr/lib/os-release") }},
+		{"/etc/redhat-release", ConfidenceMedium, readRedhatRelease},
+		{"/etc/alpine-release", ConfidenceHigh, readAlpineRelease},
+		{"/etc/debian_version", ConfidenceHigh, readDebianVersion},
+		{"/bin/busybox", ConfidenceMedium, readBusybox},
+		{"/var/lib/dpkg/status.d", ConfidenceMedium, readDistroless},
+	}
+
+	entries := map[string]string{
+		// no Operating System is found in the container
+		"os-release-id": "scratch",
+	}
+	confidence := ConfidenceLow
+	evidence := []Evidence{}
+	for _, detection := range detections {
+		if detected, found := detection.detect(ctx.Root); found {
+			entries = detected
+			confidence = detection.confidence
+			evidence = append(evidence, Evidence{Type: EvidenceFile, Path: detection.path})
+			break
+		}
+	}
+	if err := ctx.Write("os", entries, confidence, evidence...); err != nil {
+		return err
+	}
+
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+	packages, database, err := ospackages.GetInstalledPackages(ctx.Root, config.Fingerprints.OsPackages.AllowList)
+	if err != nil {
+		log.Printf("Unable to read the installed packages: %s\n", err)
+	}
+	if len(packages) > 0 {
+		return ctx.Write("os-packages", packageEntries(packages), ConfidenceHigh, Evidence{Type: EvidenceFile, Path: database})
+	}
+	return nil
+}
+
+// packageEntries returns the entries of the packages: the key is `<name>:<arch>` (or the name when the package
+// has no architecture) and the value is the comma-separated list of the installed versions, so that the packages
+// installed for several architectures (multilib) or in several versions (kernel, gpg-pubkey) are all reported.
+// The package managers do not allow ':' in the package names and ',' in the versions.
+func packageEntries(packages []ospackages.Package) map[string]string {
+	versions := make(map[string][]string)
+	for _, pkg := range packages {
+		key := pkg.Name
+		if pkg.Arch != "" {
+			key += ":" + pkg.Arch
+		}
+		if !slices.Contains(versions[key], pkg.Version) {
+			versions[key] = append(versions[key], pkg.Version)
+		}
+	}
+	entries := make(map[string]string, len(versions))
+	for key, keyVersions := range versions {
+		slices.Sort(keyVersions)
+		entries[key] = strings.Join(keyVersions, ",")
+	}
+	return entries
+}
+
+func readOsRelease(root string, path string) (map[string]string, bool) {
+	properties, exists := utils.ReadPropertiesFile(root, path)
+	if !exists {
+		return nil, false
+	}
+	entries := make(map[string]string)
+	for k, v := range properties {
+		if entry, found := osReleaseFields[k]; found {
+			entries[entry] = v
+		}
+	}
+	return entries, true
+}
+
+// readRedhatRelease reads the /etc/redhat-release file (for example "Red Hat Enterprise Linux release 9.4 (Plow)")
+func readRedhatRelease(root string) (map[string]string, bool) {
+	release, exists := readFirstLine(root, "/etc/redhat-release")
+	if !exists {
+		return nil, false
+	}
+	return parseRedhatRelease(release), true
+}
+
+// parseRedhatRelease returns the entries of the distribution named by the redhat-release line
+func parseRedhatRelease(release string) map[string]string {
+	entries := map[string]string{
+		"os-release-pretty-name": release,
+	}
+	for _, name := range redhatReleaseNames {
+		if strings.HasPrefix(release, name.prefix) {
+			entries["os-release-id"] = name.id
+			if name.idLike != "" {
+				entries["os-release-id-like"] = name.idLike
+			}
+			break
+		}
+	}
+	if matches := redhatReleaseVersion.FindStringSubmatch(release); matches != nil {
+		entries["os-release-version-id"] = matches[1]
+	}
+	return entries
+}
+
+// readAlpineRelease reads the /etc/alpine-release file (for example "3.19.1")
+func readAlpineRelease(root string) (map[string]string, bool) {
+	version, exists := readFirstLine(root, "/etc/alpine-release")
+	if !exists {
+		return nil, false
+	}
+	return map[string]string{
+		"os-release-id":         "alpine",
+		"os-release-version-id": version,
+	}, true
+}
+
+// readDebianVersion reads the /etc/debian_version file (for example "12.5" or "bookworm/sid")
+func readDebianVersion(root string) (map[string]string, bool) {
+	version, exists := readFirstLine(root, "/etc/debian_version")
+	if !exists {
+		return nil, false
+	}
+	return parseDebianVersion(version), true
+}
+
+// parseDebianVersion returns the entries of the Debian release named by the debian_version line
+// (a version for the stable releases, a codename for the testing releases)
+func parseDebianVersion(version string) map[string]string {
+	entries := map[string]string{
+		"os-release-id": "debian",
+	}
+	if codename, found := strings.CutSuffix(version, "/sid"); found {
+		entries["os-release-version-codename"] = codename
+	} else {
+		entries["os-release-version-id"] = version
+	}
+	return entries
+}
+
+// readBusybox checks for a busybox executable and extracts its version
+// from the "BusyBox vX.Y.Z" string embedded in the executable
+func readBusybox(root string) (map[string]string, bool) {
+	content, err := utils.ReadFile(root, "/bin/busybox")
+	if err != nil {
+		return nil, false
+	}
+	return parseBusybox(content), true
+}
+
+// parseBusybox returns the entries of the busybox executable with that content
+func parseBusybox(content []byte) map[string]string {
+	entries := map[string]string{
+		"os-release-id": "busybox",
+	}
+	if matches := busyboxVersion.FindSubmatch(content); matches != nil {
+		entries["os-release-version-id"] = string(matches[1])
+	}
+	return entries
+}
+
+// readDistroless checks for the /var/lib/dpkg/status.d directory that is used by distroless images
+// to list their Debian packages. The version of the base-files package gives the Debian version.
+func readDistroless(root string) (map[string]string, bool) {
+	statusDir := "/var/lib/dpkg/status.d"
+	if info, err := utils.Stat(root, statusDir); err != nil || !info.IsDir() {
+		return nil, false
+	}
+	entries := map[string]string{
+		"os-release-id":      "distroless",
+		"os-release-id-like": "debian",
+	}
+	for _, match := range utils.Glob(root, filepath.Join(statusDir, "base-files*")) {
+		file, err := utils.Open(root, match)
+		if err != nil {
+			continue
+		}
+		if version := parseBaseFilesVersion(file); version != "" {
+			entries["os-release-version-id"] = version
+		}
+		file.Close()
+	}
+	return entries, true
+}
+
+// parseBaseFilesVersion returns the Debian version from the dpkg status of the base-files package
+func parseBaseFilesVersion(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if version, found := strings.CutPrefix(scanner.Text(), "Version: "); found {
+			// for example 12.4+deb12u5
+			version, _, _ = strings.Cut(version, "+")
+			return version
+		}
+	}
+	return ""
+}
+
+func readFirstLine(root string, path string) (string, bool) {
+	content, err := utils.ReadFile(root, path)
+	if err != nil {
+		return "", false
+	}
+	line, _, _ := strings.Cut(string(content), "\n")
+	return strings.TrimSpace(line), true
+}
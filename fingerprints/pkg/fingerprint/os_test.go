@@ -0,0 +1,188 @@
+package fingerprint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"fingerprints/pkg/ospackages"
+)
+
+func TestParseRedhatRelease(t *testing.T) {
+	for _, test := range []struct {
+		release string
+		entries map[string]string
+	}{
+		{
+			release: "Red Hat Enterprise Linux release 9.4 (Plow)",
+			entries: map[string]string{"os-release-id": "rhel", "os-release-id-like": "fedora", "os-release-version-id": "9.4",
+				"os-release-pretty-name": "Red Hat Enterprise Linux release 9.4 (Plow)"},
+		},
+		{
+			// CentOS Stream is matched before CentOS
+			release: "CentOS Stream release 9",
+			entries: map[string]string{"os-release-id": "centos", "os-release-id-like": "rhel fedora", "os-release-version-id": "9",
+				"os-release-pretty-name": "CentOS Stream release 9"},
+		},
+		{
+			release: "Rocky Linux release 8.10 (Green Obsidian)",
+			entries: map[string]string{"os-release-id": "rocky", "os-release-id-like": "rhel centos fedora", "os-release-version-id": "8.10",
+				"os-release-pretty-name": "Rocky Linux release 8.10 (Green Obsidian)"},
+		},
+		{
+			release: "Fedora release 40 (Forty)",
+			entries: map[string]string{"os-release-id": "fedora", "os-release-version-id": "40", "os-release-pretty-name": "Fedora release 40 (Forty)"},
+		},
+		{
+			release: "Acme Linux",
+			entries: map[string]string{"os-release-pretty-name": "Acme Linux"},
+		},
+	} {
+		assert.Equal(t, test.entries, parseRedhatRelease(test.release), test.release)
+	}
+}
+
+func TestParseDebianVersion(t *testing.T) {
+	for _, test := range []struct {
+		version string
+		entries map[string]string
+	}{
+		{version: "12.5", entries: map[string]string{"os-release-id": "debian", "os-release-version-id": "12.5"}},
+		{version: "trixie/sid", entries: map[string]string{"os-release-id": "debian", "os-release-version-codename": "trixie"}},
+	} {
+		assert.Equal(t, test.entries, parseDebianVersion(test.version), test.version)
+	}
+}
+
+func TestParseBusybox(t *testing.T) {
+	for _, test := range []struct {
+		content string
+		entries map[string]string
+	}{
+		{content: "\x7fELF\x00BusyBox v1.36.1 (2024-01-01 00:00:00 UTC)\x00", entries: map[string]string{"os-release-id": "busybox", "os-release-version-id": "1.36.1"}},
+		{content: "\x7fELF\x00", entries: map[string]string{"os-release-id": "busybox"}},
+	} {
+		assert.Equal(t, test.entries, parseBusybox([]byte(test.content)), test.content)
+	}
+}
+
+func TestParseBaseFilesVersion(t *testing.T) {
+	for _, test := range []struct {
+		status  string
+		version string
+	}{
+		{status: "Package: base-files\nVersion: 12.4+deb12u5\nArchitecture: amd64\n", version: "12.4"},
+		{status: "Package: base-files\nVersion: 11.1\n", version: "11.1"},
+		{status: "Package: base-files\nArchitecture: amd64\n", version: ""},
+	} {
+		assert.Equal(t, test.version, parseBaseFilesVersion(strings.NewReader(test.status)), test.status)
+	}
+}
+
+func TestOsReleaseFallbacks(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		files   map[string]string
+		detect  func(root string) (map[string]string, bool)
+		entries map[string]string
+	}{
+		{
+			name:   "rhel",
+			files:  map[string]string{"/etc/redhat-release": "Red Hat Enterprise Linux release 9.4 (Plow)\n"},
+			detect: readRedhatRelease,
+			entries: map[string]string{"os-release-id": "rhel", "os-release-id-like": "fedora", "os-release-version-id": "9.4",
+				"os-release-pretty-name": "Red Hat Enterprise Linux release 9.4 (Plow)"},
+		},
+		{
+			// CentOS Stream is matched before CentOS
+			name:   "centos stream",
+			files:  map[string]string{"/etc/redhat-release": "CentOS Stream release 9\n"},
+			detect: readRedhatRelease,
+			entries: map[string]string{"os-release-id": "centos", "os-release-id-like": "rhel fedora", "os-release-version-id": "9",
+				"os-release-pretty-name": "CentOS Stream release 9"},
+		},
+		{
+			name:    "fedora",
+			files:   map[string]string{"/etc/redhat-release": "Fedora release 40 (Forty)\n"},
+			detect:  readRedhatRelease,
+			entries: map[string]string{"os-release-id": "fedora", "os-release-version-id": "40", "os-release-pretty-name": "Fedora release 40 (Forty)"},
+		},
+		{
+			name:    "unknown redhat-release distribution",
+			files:   map[string]string{"/etc/redhat-release": "Acme Linux\n"},
+			detect:  readRedhatRelease,
+			entries: map[string]string{"os-release-pretty-name": "Acme Linux"},
+		},
+		{
+			name:    "alpine",
+			files:   map[string]string{"/etc/alpine-release": "3.19.1\n"},
+			detect:  readAlpineRelease,
+			entries: map[string]string{"os-release-id": "alpine", "os-release-version-id": "3.19.1"},
+		},
+		{
+			name:    "debian",
+			files:   map[string]string{"/etc/debian_version": "12.5\n"},
+			detect:  readDebianVersion,
+			entries: map[string]string{"os-release-id": "debian", "os-release-version-id": "12.5"},
+		},
+		{
+			name:    "debian testing",
+			files:   map[string]string{"/etc/debian_version": "trixie/sid\n"},
+			detect:  readDebianVersion,
+			entries: map[string]string{"os-release-id": "debian", "os-release-version-codename": "trixie"},
+		},
+		{
+			name:    "busybox",
+			files:   map[string]string{"/bin/busybox": "\x7fELF\x00BusyBox v1.36.1 (2024-01-01 00:00:00 UTC)\x00"},
+			detect:  readBusybox,
+			entries: map[string]string{"os-release-id": "busybox", "os-release-version-id": "1.36.1"},
+		},
+		{
+			name:    "busybox without version",
+			files:   map[string]string{"/bin/busybox": "\x7fELF\x00"},
+			detect:  readBusybox,
+			entries: map[string]string{"os-release-id": "busybox"},
+		},
+		{
+			name: "distroless",
+			files: map[string]string{
+				"/var/lib/dpkg/status.d/base-files": "Package: base-files\nVersion: 12.4+deb12u5\nArchitecture: amd64\n",
+				"/var/lib/dpkg/status.d/libc6":      "Package: libc6\nVersion: 2.36-9+deb12u7\n",
+			},
+			detect:  readDistroless,
+			entries: map[string]string{"os-release-id": "distroless", "os-release-id-like": "debian", "os-release-version-id": "12.4"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, content := range test.files {
+				writeRootFile(t, root, path, content)
+			}
+			entries, found := test.detect(root)
+			assert.True(t, found)
+			assert.Equal(t, test.entries, entries)
+
+			// the file does not exist
+			_, found = test.detect(t.TempDir())
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestPackageEntries(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"glibc:x86_64":       "2.34-100.el9",
+		"glibc:i686":         "2.34-100.el9",
+		"kernel-core:x86_64": "5.14.0-427.13.1.el9_4,5.14.0-427.16.1.el9_4",
+		"ca-certificates":    "20240203",
+	}, packageEntries([]ospackages.Package{
+		{Name: "glibc", Version: "2.34-100.el9", Arch: "x86_64"},
+		{Name: "kernel-core", Version: "5.14.0-427.16.1.el9_4", Arch: "x86_64"},
+		{Name: "glibc", Version: "2.34-100.el9", Arch: "i686"},
+		{Name: "kernel-core", Version: "5.14.0-427.13.1.el9_4", Arch: "x86_64"},
+		// the same package can be listed twice by the dpkg status directory of distroless images
+		{Name: "ca-certificates", Version: "20240203"},
+		{Name: "ca-certificates", Version: "20240203"},
+	}))
+}
@@ -0,0 +1,141 @@
+package fingerprint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"keyvalue"
+
+	"fingerprints/pkg/utils"
+)
+
+// PluginDirEnv is the environment variable setting the directory of the fingerprint plugins.
+// The plugins are in the plugins directory alongside the fpr binary if it is not set.
+const PluginDirEnv = "FPR_PLUGIN_DIR"
+
+// Plugins fingerprints the runtime components detected by the external fingerprint executables of the plugin directory.
+//
+// Each plugin declares in its manifest the processes and the files of the container that it applies to
+// (see utils.PluginManifest). Its executable reads the process context and the configuration of the output
+// directory like the other fingerprints and prints the components that it detects as key=value lines:
+// they are written as runtimes (or agents) results so that in-house frameworks are reported
+// without a fingerprint of the fpr binary.
+type Plugins struct{}
+
+func init() {
+	Register(&Plugins{})
+}
+
+func (*Plugins) Name() string {
+	return "plugins"
+}
+
+func (*Plugins) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, plugin := range readPlugins() {
+		if !plugin.AppliesTo(process.Name) {
+			continue
+		}
+		found, err := pluginFilesExist(ctx.Root, plugin, process)
+		if err != nil {
+			return fmt.Errorf("invalid plugin %s: %w", plugin.Name, err)
+		}
+		if !found {
+			continue
+		}
+
+		log.Printf("Running the %s plugin\n", plugin.Name)
+		output, err := utils.RunPlugin(plugin, ctx.Root, ctx.OutputDir)
+		if err != nil {
+			var budgetErr *utils.BudgetExceededError
+			if errors.As(err, &budgetErr) {
+				return err
+			}
+			// the failure of a plugin does not prevent the other plugins from running
+			log.Printf("⚠️ %s\n", err)
+			errs = append(errs, err)
+			continue
+		}
+		entries, err := keyvalue.Read(bytes.NewReader(output))
+		if err != nil {
+			err = fmt.Errorf("invalid output of the %s plugin: %w", plugin.Name, err)
+			log.Printf("⚠️ %s\n", err)
+			errs = append(errs, err)
+			continue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		log.Printf("Found %v from the %s plugin\n", entries, plugin.Name)
+		evidence := Evidence{Type: EvidenceCommandOutput, Path: plugin.ExecutablePath(), Detail: plugin.Name}
+		if err := ctx.Write(pluginKind(plugin), entries, pluginConfidence(plugin), evidence); err != nil {
+			return err
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pluginDir returns the directory of the fingerprint plugins
+func pluginDir() string {
+	if dir := os.Getenv(PluginDirEnv); dir != "" {
+		return dir
+	}
+	return filepath.Join(filepath.Dir(os.Args[0]), "plugins")
+}
+
+// readPlugins returns the valid plugins of the plugin directory (the invalid plugins are logged)
+func readPlugins() []utils.PluginManifest {
+	plugins, err := utils.ReadPlugins(pluginDir())
+	if err != nil {
+		log.Printf("⚠️ Ignoring invalid plugins: %s\n", err)
+	}
+	return plugins
+}
+
+// pluginKind returns the kind of the results of the plugin
+func pluginKind(plugin utils.PluginManifest) string {
+	if plugin.Output == utils.PluginOutputAgents {
+		return "agents-fingerprints"
+	}
+	return "plugins-fingerprints"
+}
+
+// pluginConfidence returns the confidence of the components printed by the plugin
+func pluginConfidence(plugin utils.PluginManifest) Confidence {
+	if plugin.Confidence == "" {
+		return ConfidenceMedium
+	}
+	return Confidence(plugin.Confidence)
+}
+
+// pluginFilesExist returns true if each file pattern of the plugin matches a file of the container
+func pluginFilesExist(root string, plugin utils.PluginManifest, process utils.ProcessContext) (bool, error) {
+	if len(plugin.Files) == 0 {
+		return true, nil
+	}
+	baseDir, err := fileRuleBaseDir(root, plugin.RelativeTo, process)
+	if err != nil {
+		return false, err
+	}
+	for _, pattern := range plugin.Files {
+		if !filepath.IsAbs(pattern) {
+			if baseDir == "" {
+				return false, nil
+			}
+			pattern = filepath.Join(baseDir, pattern)
+		}
+		if len(utils.Glob(root, pattern)) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readPluginsResults returns the results of that kind written by the plugins fingerprint to the output directory
+func readPluginsResults(t *testing.T, outputDir string, kind string) []Result {
+	files, _ := filepath.Glob(filepath.Join(outputDir, kind+".plugins.*.json"))
+	results := []Result{}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		var result Result
+		assert.NoError(t, json.Unmarshal(content, &result))
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestPlugins(t *testing.T) {
+	pluginDir := t.TempDir()
+	t.Setenv(PluginDirEnv, pluginDir)
+	writeRootFile(t, pluginDir, "acme/plugin.toml", `
+executable = "bin/acme-fingerprint"
+process-names = ["java", "acme-*"]
+relative-to = "cwd"
+files = ["lib/acme-core-*.jar"]
+confidence = "high"
+`)
+	// the plugin prints its arguments and the name of the process from the process context
+	writeRootFile(t, pluginDir, "acme/bin/acme-fingerprint", "#!/bin/sh\n"+
+		"echo \"ACME Framework=3.2.1\"\n"+
+		"echo \"ACME Arguments=$1 $2\"\n"+
+		"grep -q acme-server \"$3/process.json\" && echo \"ACME Server=3.2.1\"\n"+
+		"exit 0\n")
+	assert.NoError(t, os.Chmod(filepath.Join(pluginDir, "acme/bin/acme-fingerprint"), 0755))
+	writeRootFile(t, pluginDir, "tracer/plugin.toml", "name = \"acme-tracer\"\nexecutable = \"tracer.sh\"\noutput = \"agents\"\n")
+	writeRootFile(t, pluginDir, "tracer/tracer.sh", "#!/bin/sh\necho 'ACME Tracer=1.0'\n")
+	assert.NoError(t, os.Chmod(filepath.Join(pluginDir, "tracer/tracer.sh"), 0755))
+	// the invalid plugins are ignored
+	writeRootFile(t, pluginDir, "broken/plugin.toml", "executable = \"/usr/bin/broken\"\n")
+
+	root := t.TempDir()
+	writeRootFile(t, root, "/opt/acme/lib/acme-core-3.2.1.jar", "")
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", "")
+	writeProcess(t, outputDir, "/opt/acme", nil, "/opt/acme/bin/acme-server")
+
+	assert.NoError(t, Run("plugins", []string{"--root", root, outputDir}))
+	runtimes := readPluginsResults(t, outputDir, "plugins-fingerprints")
+	if assert.Len(t, runtimes, 1) {
+		assert.Equal(t, map[string]string{
+			"ACME Framework": "3.2.1",
+			"ACME Arguments": "--root " + root,
+			"ACME Server":    "3.2.1",
+		}, runtimes[0].Values)
+		assert.Equal(t, ConfidenceHigh, runtimes[0].Confidence)
+		assert.Equal(t, []Evidence{{Type: EvidenceCommandOutput, Path: filepath.Join(pluginDir, "acme/bin/acme-fingerprint"), Detail: "acme"}}, runtimes[0].Evidence)
+	}
+	agents := readPluginsResults(t, outputDir, "agents-fingerprints")
+	if assert.Len(t, agents, 1) {
+		assert.Equal(t, map[string]string{"ACME Tracer": "1.0"}, agents[0].Values)
+		assert.Equal(t, ConfidenceMedium, agents[0].Confidence)
+	}
+
+	// the acme plugin does not apply to a process without its files
+	outputDir = t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", "")
+	writeProcess(t, outputDir, "/app", nil, "/app/acme-server")
+	assert.NoError(t, Run("plugins", []string{"--root", root, outputDir}))
+	assert.Empty(t, readPluginsResults(t, outputDir, "plugins-fingerprints"))
+	assert.Len(t, readPluginsResults(t, outputDir, "agents-fingerprints"), 1)
+}
+
+func TestPluginsFailure(t *testing.T) {
+	pluginDir := t.TempDir()
+	t.Setenv(PluginDirEnv, pluginDir)
+	writeRootFile(t, pluginDir, "failing/plugin.toml", "executable = \"run\"\n")
+	writeRootFile(t, pluginDir, "failing/run", "#!/bin/sh\nexit 3\n")
+	assert.NoError(t, os.Chmod(filepath.Join(pluginDir, "failing/run"), 0755))
+	writeRootFile(t, pluginDir, "working/plugin.toml", "executable = \"run\"\n")
+	writeRootFile(t, pluginDir, "working/run", "#!/bin/sh\necho 'Working=1.0'\n")
+	assert.NoError(t, os.Chmod(filepath.Join(pluginDir, "working/run"), 0755))
+
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", "")
+	writeProcess(t, outputDir, "/", nil, "/usr/bin/app")
+	// the failure of a plugin does not prevent the other plugins from running
+	assert.EqualError(t, Run("plugins", []string{outputDir}), "plugins fingerprint failed: plugin failing failed: exit status 3")
+	runtimes := readPluginsResults(t, outputDir, "plugins-fingerprints")
+	if assert.Len(t, runtimes, 1) {
+		assert.Equal(t, map[string]string{"Working": "1.0"}, runtimes[0].Values)
+	}
+}
@@ -0,0 +1,253 @@
+package fingerprint
+
+import (
+	"debug/buildinfo"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// Environment variables that can change the security posture of the process
+var postureEnvVars = []string{
+	"JAVA_TOOL_OPTIONS",
+	"JDK_JAVA_OPTIONS",
+	"NODE_OPTIONS",
+	"NODE_ENV",
+	"PYTHONDEVMODE",
+}
+
+// Posture fingerprints risky configurations (for example debug ports) from the launch configuration of the process
+type Posture struct{}
+
+func init() {
+	Register(&Posture{})
+}
+
+func (*Posture) Name() string {
+	return "posture"
+}
+
+func (*Posture) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	cwd := process.Cwd
+	// the environment variables that are relevant to the posture
+	environ := make(map[string]string)
+	for _, name := range postureEnvVars {
+		if value, exists := process.Environ[name]; exists {
+			environ[name] = value
+		}
+	}
+	commandLine := process.CommandLine
+	if len(commandLine) == 0 {
+		return nil
+	}
+
+	findings := checkPosture(ctx.Root, cwd, environ, commandLine)
+	if len(findings) > 0 {
+		evidence := []Evidence{{Type: EvidenceCommandLine}}
+		for _, name := range triggeringEnvVars(ctx.Root, cwd, environ, commandLine[0]) {
+			evidence = append(evidence, Evidence{Type: EvidenceEnvironment, Detail: name})
+		}
+		return ctx.Write("posture", findings, ConfidenceHigh, evidence...)
+	}
+	return nil
+}
+
+// checkPosture returns the findings of the command line and the environment variables of a process
+func checkPosture(root string, cwd string, environ map[string]string, commandLine []string) map[string]string {
+	findings := make(map[string]string)
+	executable := filepath.Base(commandLine[0])
+	switch {
+	case strings.HasSuffix(executable, "java"):
+		javaArgs := slices.Concat(strings.Fields(environ["JAVA_TOOL_OPTIONS"]), strings.Fields(environ["JDK_JAVA_OPTIONS"]), commandLine[1:])
+		checkJavaPosture(root, findings, cwd, javaArgs)
+	case strings.HasSuffix(executable, "node"):
+		nodeArgs := append(strings.Fields(environ["NODE_OPTIONS"]), commandLine[1:]...)
+		checkNodePosture(findings, environ, nodeArgs)
+	case strings.Contains(executable, "python"):
+		checkPythonPosture(findings, environ, commandLine[1:])
+	default:
+		executablePath := commandLine[0]
+		if !filepath.IsAbs(executablePath) {
+			executablePath = filepath.Join(cwd, executablePath)
+		}
+		checkGoPosture(root, findings, executablePath)
+	}
+	return findings
+}
+
+// triggeringEnvVars returns the environment variables (in the order of postureEnvVars) that produce a finding
+// on their own, when the executable is run without arguments
+func triggeringEnvVars(root string, cwd string, environ map[string]string, executable string) []string {
+	withoutEnv := checkPosture(root, cwd, nil, []string{executable})
+	names := []string{}
+	for _, name := range postureEnvVars {
+		value, exists := environ[name]
+		if !exists {
+			continue
+		}
+		for finding := range checkPosture(root, cwd, map[string]string{name: value}, []string{executable}) {
+			if _, found := withoutEnv[finding]; !found {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// Options of the node executable whose value is the next argument
+var nodeOptionsWithValue = []string{
+	"-r", "--require", "-e", "--eval", "-p", "--print", "--import",
+	"--loader", "--experimental-loader", "-C", "--conditions", "--title",
+}
+
+// checkJavaPosture checks the options of the java launcher.
+// The arguments that follow the main class, the main module or the executable jar are application arguments.
+func checkJavaPosture(root string, findings map[string]string, cwd string, args []string) {
+	jmxUnauthenticated := false
+	jmxPort := ""
+	jar, classpath := "", ""
+options:
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-jar":
+			if i+1 < len(args) {
+				jar = absolutePath(cwd, args[i+1])
+			}
+			break options
+		case arg == "-m" || arg == "--module" || !strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "@"):
+			break options
+		case arg == "-cp" || arg == "-classpath" || arg == "--class-path":
+			if i+1 >= len(args) {
+				continue
+			}
+			i++
+			classpath = ""
+			for _, entry := range strings.Split(args[i], string(os.PathListSeparator)) {
+				classpath = classpath + absolutePath(cwd, entry) + string(os.PathListSeparator)
+			}
+		case slices.Contains(optionsWithValue, arg):
+			i++
+		case strings.HasPrefix(arg, "-agentlib:jdwp"):
+			findings["java-jdwp-agent"] = strings.TrimPrefix(strings.TrimPrefix(arg, "-agentlib:jdwp"), "=")
+		case strings.HasPrefix(arg, "-Xrunjdwp"):
+			findings["java-jdwp-agent"] = strings.TrimPrefix(strings.TrimPrefix(arg, "-Xrunjdwp"), ":")
+		case arg == "-Dcom.sun.management.jmxremote.authenticate=false":
+			jmxUnauthenticated = true
+		case strings.HasPrefix(arg, "-Dcom.sun.management.jmxremote.port="):
+			jmxPort = strings.TrimPrefix(arg, "-Dcom.sun.management.jmxremote.port=")
+		}
+	}
+	if jmxUnauthenticated {
+		findings["java-jmx-unauthenticated"] = jmxPort
+	}
+	if jar != "" || classpath != "" {
+		if version, found := findSpringBootDevTools(root, utils.GetClasspathJars(root, jar, classpath)); found {
+			findings["spring-boot-devtools"] = version
+		}
+	}
+}
+
+// findSpringBootDevTools checks whether Spring Boot DevTools is on the classpath (or nested in one of its jars)
+// and returns its version
+func findSpringBootDevTools(root string, classpathJars []string) (string, bool) {
+	for _, jar := range classpathJars {
+		if version, found := utils.MatchJarVersion(jar, "spring-boot-devtools"); found {
+			return version, true
+		}
+		for _, nestedJar := range utils.ListNestedJars(root, jar) {
+			if version, found := utils.MatchJarVersion(nestedJar, "spring-boot-devtools"); found {
+				return version, true
+			}
+		}
+	}
+	return "", false
+}
+
+// checkNodePosture checks the options of the node executable.
+// The arguments that follow the script are application arguments.
+func checkNodePosture(findings map[string]string, environ map[string]string, args []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		if slices.Contains(nodeOptionsWithValue, arg) {
+			i++
+		} else if strings.HasPrefix(arg, "--inspect") {
+			findings["node-inspector"] = arg
+		}
+	}
+	if environ["NODE_ENV"] == "development" {
+		findings["node-env-development"] = ""
+	}
+}
+
+// checkPythonPosture checks the options of the python interpreter.
+// The arguments that follow the script, the command (-c) or the module (-m) are application arguments.
+func checkPythonPosture(findings map[string]string, environ map[string]string, args []string) {
+options:
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		if strings.HasPrefix(arg, "--") {
+			if arg == "--check-hash-based-pycs" {
+				i++
+			}
+			continue
+		}
+		// the single-letter options can be grouped (for example -BX dev)
+		for j := 1; j < len(arg); j++ {
+			switch arg[j] {
+			case 'c', 'm':
+				break options
+			case 'X', 'W':
+				value := arg[j+1:]
+				if value == "" && i+1 < len(args) {
+					i++
+					value = args[i]
+				}
+				if arg[j] == 'X' && value == "dev" {
+					findings["python-dev-mode"] = ""
+				}
+				continue options
+			}
+		}
+	}
+	if devMode, exists := environ["PYTHONDEVMODE"]; exists && devMode != "" {
+		findings["python-dev-mode"] = ""
+	}
+}
+
+func checkGoPosture(root string, findings map[string]string, executable string) {
+	executable, err := utils.ResolvePath(root, executable)
+	if err != nil {
+		return
+	}
+	bi, err := buildinfo.ReadFile(executable)
+	if err != nil {
+		return
+	}
+	for _, setting := range bi.Settings {
+		if setting.Key == "-race" && setting.Value == "true" {
+			findings["go-race-detector"] = ""
+		}
+	}
+}
+
+func absolutePath(cwd string, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(cwd, path)
+}
@@ -0,0 +1,162 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckJavaPosture(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		findings map[string]string
+	}{
+		{
+			args:     []string{"-agentlib:jdwp=transport=dt_socket,server=y,address=*:5005", "-jar", "app.jar"},
+			findings: map[string]string{"java-jdwp-agent": "transport=dt_socket,server=y,address=*:5005"},
+		},
+		{
+			args:     []string{"-Xrunjdwp:transport=dt_socket", "-cp", "app.jar", "com.acme.Main"},
+			findings: map[string]string{"java-jdwp-agent": "transport=dt_socket"},
+		},
+		{
+			args:     []string{"-Dcom.sun.management.jmxremote.port=9010", "-Dcom.sun.management.jmxremote.authenticate=false", "-jar", "app.jar"},
+			findings: map[string]string{"java-jmx-unauthenticated": "9010"},
+		},
+		// the application arguments are not options of the JVM
+		{
+			args:     []string{"-jar", "app.jar", "-Dcom.sun.management.jmxremote.authenticate=false", "-agentlib:jdwp=transport=dt_socket"},
+			findings: map[string]string{},
+		},
+		{
+			args:     []string{"-cp", "app.jar", "--add-opens", "java.base/java.lang=ALL-UNNAMED", "com.acme.Main", "-agentlib:jdwp=transport=dt_socket"},
+			findings: map[string]string{},
+		},
+		{
+			args:     []string{"-p", "mods", "-m", "com.acme/com.acme.Main", "-Dcom.sun.management.jmxremote.authenticate=false"},
+			findings: map[string]string{},
+		},
+	} {
+		findings := map[string]string{}
+		checkJavaPosture("", findings, "/app", test.args)
+		assert.Equal(t, test.findings, findings, test.args)
+	}
+}
+
+func TestCheckNodePosture(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		environ  map[string]string
+		findings map[string]string
+	}{
+		{
+			args:     []string{"--inspect=0.0.0.0:9229", "server.js"},
+			findings: map[string]string{"node-inspector": "--inspect=0.0.0.0:9229"},
+		},
+		{
+			args:     []string{"-r", "dotenv/config", "--inspect-brk", "server.js"},
+			environ:  map[string]string{"NODE_ENV": "development"},
+			findings: map[string]string{"node-inspector": "--inspect-brk", "node-env-development": ""},
+		},
+		// the application arguments are not options of node
+		{
+			args:     []string{"server.js", "--inspect-me"},
+			findings: map[string]string{},
+		},
+		{
+			args:     []string{"--require", "--inspect", "server.js"},
+			findings: map[string]string{},
+		},
+		{
+			args:     []string{"--", "--inspect"},
+			environ:  map[string]string{"NODE_ENV": "production"},
+			findings: map[string]string{},
+		},
+	} {
+		findings := map[string]string{}
+		checkNodePosture(findings, test.environ, test.args)
+		assert.Equal(t, test.findings, findings, test.args)
+	}
+}
+
+func TestCheckPythonPosture(t *testing.T) {
+	for _, test := range []struct {
+		args     []string
+		environ  map[string]string
+		findings map[string]string
+	}{
+		{args: []string{"-X", "dev", "app.py"}, findings: map[string]string{"python-dev-mode": ""}},
+		{args: []string{"-Xdev", "-m", "flask"}, findings: map[string]string{"python-dev-mode": ""}},
+		{args: []string{"-BX", "dev", "app.py"}, findings: map[string]string{"python-dev-mode": ""}},
+		{args: []string{"app.py"}, environ: map[string]string{"PYTHONDEVMODE": "1"}, findings: map[string]string{"python-dev-mode": ""}},
+		{args: []string{"-X", "utf8", "app.py"}, findings: map[string]string{}},
+		// the application arguments are not options of the interpreter
+		{args: []string{"app.py", "-X", "dev"}, findings: map[string]string{}},
+		{args: []string{"-m", "pytest", "-Xdev"}, findings: map[string]string{}},
+		{args: []string{"-c", "import sys", "-X", "dev"}, findings: map[string]string{}},
+		{args: []string{"-W", "-Xdev", "app.py"}, findings: map[string]string{}},
+	} {
+		findings := map[string]string{}
+		checkPythonPosture(findings, test.environ, test.args)
+		assert.Equal(t, test.findings, findings, test.args)
+	}
+}
+
+func TestPostureEvidence(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		environ     map[string]string
+		commandLine []string
+		findings    map[string]string
+		envVars     []string
+	}{
+		{
+			// JDK_JAVA_OPTIONS and NODE_ENV do not produce any finding
+			name: "java options",
+			environ: map[string]string{"JAVA_TOOL_OPTIONS": "-agentlib:jdwp=transport=dt_socket", "JDK_JAVA_OPTIONS": "-Xmx512m",
+				"NODE_ENV": "development"},
+			commandLine: []string{"/usr/bin/java", "-jar", "app.jar"},
+			findings:    map[string]string{"java-jdwp-agent": "transport=dt_socket"},
+			envVars:     []string{"JAVA_TOOL_OPTIONS"},
+		},
+		{
+			name:        "command line",
+			environ:     map[string]string{"NODE_OPTIONS": "--max-old-space-size=512"},
+			commandLine: []string{"/usr/bin/node", "--inspect", "app.js"},
+			findings:    map[string]string{"node-inspector": "--inspect"},
+			envVars:     []string{},
+		},
+		{
+			name:        "node environment",
+			environ:     map[string]string{"NODE_OPTIONS": "--inspect=0.0.0.0:9229", "NODE_ENV": "development"},
+			commandLine: []string{"/usr/bin/node", "app.js"},
+			findings:    map[string]string{"node-inspector": "--inspect=0.0.0.0:9229", "node-env-development": ""},
+			envVars:     []string{"NODE_OPTIONS", "NODE_ENV"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			outputDir := t.TempDir()
+			writeProcess(t, outputDir, "/app", test.environ, test.commandLine...)
+
+			assert.NoError(t, Run("posture", []string{"--root", t.TempDir(), outputDir}))
+			files, _ := filepath.Glob(filepath.Join(outputDir, "posture.posture.*.json"))
+			if assert.Len(t, files, 1) {
+				content, err := os.ReadFile(files[0])
+				assert.NoError(t, err)
+				var result Result
+				assert.NoError(t, json.Unmarshal(content, &result))
+				assert.Equal(t, test.findings, result.Values)
+				envVars := []string{}
+				for _, evidence := range result.Evidence {
+					if evidence.Type == EvidenceEnvironment {
+						envVars = append(envVars, evidence.Detail)
+					}
+				}
+				assert.Equal(t, test.envVars, envVars)
+			}
+		})
+	}
+}
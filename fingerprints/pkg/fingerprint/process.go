@@ -0,0 +1,114 @@
+package fingerprint
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// Process fingerprints a process by running all the fingerprints that apply to it.
+//
+// It selects the fingerprints like the extractor does for the processes of a running container
+// (see the fingerprint module of the extractor) and is used to fingerprint a process that is not running
+// (for example the process described by the configuration of an image).
+// The fingerprints that it runs read the same process context.
+// Each fingerprint runs in its own fpr process, like in the extractor, so that a fingerprint abandoned at its timeout
+// is stopped with its process instead of running (and using the budgets) during the next fingerprints.
+type Process struct{}
+
+func init() {
+	Register(&Process{})
+}
+
+func (*Process) runsFingerprints() {}
+
+func (*Process) Name() string {
+	return "process"
+}
+
+func (*Process) Fingerprint(ctx *Context) error {
+	process, err := ctx.Process()
+	if err != nil {
+		return err
+	}
+	config, err := ctx.Config()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range selectFingerprints(config, readPlugins(), process) {
+		args := []string{ctx.OutputDir}
+		if ctx.Root != "" {
+			args = append([]string{"--root", ctx.Root}, args...)
+		}
+		// like the extractor, the failure of a fingerprint does not prevent the other fingerprints from running
+		if err := runChild(name, args); err != nil {
+			log.Printf("⚠️ %s\n", err)
+		}
+	}
+	return nil
+}
+
+// runChild runs the fingerprint in a child fpr process
+func runChild(name string, args []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to run the %s fingerprint: %w", name, err)
+	}
+	cmd := exec.Command(executable)
+	// the child is not run as fpr_<fingerprint> even if this process is
+	cmd.Args = append([]string{"fpr", name}, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s fingerprint failed: %w", name, err)
+	}
+	return nil
+}
+
+// selectFingerprints returns the names of the fingerprints that apply to the process
+func selectFingerprints(config utils.Config, plugins []utils.PluginManifest, process utils.ProcessContext) []string {
+	executable := process.Executable()
+	isJava := strings.HasSuffix(process.Name, "java")
+	// Node.js, Python and Java processes have a `--version` and can be instrumented by agents
+	isVersionExecutable := strings.HasSuffix(process.Name, "node") || strings.Contains(executable, "python") || strings.Contains(executable, "java")
+
+	fingerprints := []string{"os", "base-image"}
+
+	if slices.ContainsFunc(config.Fingerprints.VersionExecutables, func(ve utils.VersionExecutable) bool {
+		return slices.Contains(ve.ProcessNames, process.Name)
+	}) {
+		fingerprints = append(fingerprints, "kind-executable")
+	} else if strings.Contains(executable, "java") {
+		fingerprints = append(fingerprints, "java-version")
+	}
+
+	if isJava {
+		fingerprints = append(fingerprints, "java-runtimes", "java-namespace", "java-options")
+	}
+
+	if !isVersionExecutable {
+		fingerprints = append(fingerprints, "native-executable")
+	} else {
+		fingerprints = append(fingerprints, "agents")
+	}
+
+	// a file rule without process names applies to all the processes
+	if slices.ContainsFunc(config.Fingerprints.FileRules, func(rule utils.FileRule) bool {
+		return len(rule.ProcessNames) == 0 || slices.Contains(rule.ProcessNames, process.Name)
+	}) {
+		fingerprints = append(fingerprints, "file-rules")
+	}
+
+	// the plugins fingerprint checks the files of the plugins that apply to the process
+	if slices.ContainsFunc(plugins, func(plugin utils.PluginManifest) bool { return plugin.AppliesTo(process.Name) }) {
+		fingerprints = append(fingerprints, "plugins")
+	}
+
+	return append(fingerprints, "posture", "crypto")
+}
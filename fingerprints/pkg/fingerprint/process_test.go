@@ -0,0 +1,132 @@
+package fingerprint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"fingerprints/pkg/utils"
+)
+
+var processTestConfig = utils.Config{Fingerprints: utils.Fingerprints{
+	VersionExecutables: []utils.VersionExecutable{{ProcessNames: []string{"node"}, RuntimeKindName: "Node.js"}},
+	Java:               []utils.JavaRuntimeExecutables{{RuntimeName: "Apache Tomcat", MainClass: "org.apache.catalina.startup.Bootstrap", MainJar: "bootstrap.jar"}},
+	FileRules:          []utils.FileRule{{RuntimeName: "WordPress", ProcessNames: []string{"php-fpm"}}},
+}}
+
+var processTestPlugins = []utils.PluginManifest{{Name: "acme", ProcessNames: []string{"acme-*"}}}
+
+func TestSelectFingerprints(t *testing.T) {
+	fingerprints := selectFingerprints(processTestConfig, processTestPlugins, utils.ProcessContext{
+		Name:        "java",
+		Cwd:         "/opt/tomcat",
+		Environ:     map[string]string{"PATH": "/usr/bin", "JAVA_TOOL_OPTIONS": "-Xmx1g"},
+		CommandLine: []string{"java", "-cp", "bin/bootstrap.jar:bin/tomcat-juli.jar", "org.apache.catalina.startup.Bootstrap", "start"},
+	})
+	assert.Equal(t, []string{"os", "base-image", "java-version", "java-runtimes", "java-namespace", "java-options", "agents", "posture", "crypto"}, fingerprints)
+
+	fingerprints = selectFingerprints(processTestConfig, processTestPlugins, utils.ProcessContext{
+		Name:        "node",
+		Cwd:         "/app",
+		Environ:     map[string]string{"PATH": "/usr/local/bin"},
+		CommandLine: []string{"node", "server.js"},
+	})
+	assert.Equal(t, []string{"os", "base-image", "kind-executable", "agents", "posture", "crypto"}, fingerprints)
+
+	fingerprints = selectFingerprints(processTestConfig, processTestPlugins, utils.ProcessContext{
+		Name:        "server",
+		Cwd:         "/",
+		CommandLine: []string{"/usr/local/bin/server"},
+	})
+	assert.Equal(t, []string{"os", "base-image", "native-executable", "posture", "crypto"}, fingerprints)
+
+	fingerprints = selectFingerprints(processTestConfig, processTestPlugins, utils.ProcessContext{
+		Name:        "php-fpm",
+		Cwd:         "/var/www/html",
+		CommandLine: []string{"php-fpm"},
+	})
+	assert.Equal(t, []string{"os", "base-image", "native-executable", "file-rules", "posture", "crypto"}, fingerprints)
+
+	fingerprints = selectFingerprints(processTestConfig, processTestPlugins, utils.ProcessContext{
+		Name:        "acme-server",
+		Cwd:         "/opt/acme",
+		CommandLine: []string{"/opt/acme/bin/acme-server"},
+	})
+	assert.Equal(t, []string{"os", "base-image", "native-executable", "plugins", "posture", "crypto"}, fingerprints)
+}
+
+func TestProcess(t *testing.T) {
+	t.Setenv(PluginDirEnv, t.TempDir())
+	root := t.TempDir()
+	writeRootFile(t, root, "/etc/os-release", "ID=\"rhel\"\nVERSION_ID=\"9.4\"\n")
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", "[fingerprints.budgets]\ntimeout = \"10s\"\n")
+	writeProcess(t, outputDir, "/", nil, "/usr/local/bin/server")
+
+	assert.NoError(t, Run("process", []string{"--root", root, outputDir}))
+	// each fingerprint runs in its own process and writes its own diagnostic
+	diagnostics, _ := filepath.Glob(filepath.Join(outputDir, "diagnostic.*.json"))
+	fingerprints := []string{}
+	for _, diagnostic := range diagnostics {
+		// diagnostic.<fingerprint>.<suffix>.json
+		fingerprints = append(fingerprints, strings.Split(filepath.Base(diagnostic), ".")[1])
+	}
+	assert.ElementsMatch(t, []string{"process", "os", "base-image", "native-executable", "posture", "crypto"}, fingerprints)
+	results, _ := filepath.Glob(filepath.Join(outputDir, "os.os.*.json"))
+	if assert.Len(t, results, 1) {
+		content, err := os.ReadFile(results[0])
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"os-release-version-id": "9.4"`)
+	}
+}
+
+func TestSelectJavaApplication(t *testing.T) {
+	application, found := selectJavaApplication(processTestConfig, utils.ProcessContext{
+		Name:        "java",
+		Cwd:         "/opt/tomcat",
+		CommandLine: []string{"java", "-cp", "bin/bootstrap.jar:bin/tomcat-juli.jar", "-Dcatalina.base=/opt/tomcat", "org.apache.catalina.startup.Bootstrap", "start"},
+	})
+	assert.True(t, found)
+	assert.Equal(t, javaApplication{
+		jar:       "/opt/tomcat/bin/bootstrap.jar",
+		classpath: "/opt/tomcat/bin/bootstrap.jar:/opt/tomcat/bin/tomcat-juli.jar",
+		mainClass: "org.apache.catalina.startup.Bootstrap",
+	}, application)
+
+	application, found = selectJavaApplication(processTestConfig, utils.ProcessContext{
+		Name:        "java",
+		Cwd:         "/app",
+		CommandLine: []string{"java", "-Xmx1g", "-jar", "app.jar"},
+	})
+	assert.True(t, found)
+	assert.Equal(t, javaApplication{jar: "/app/app.jar"}, application)
+
+	application, found = selectJavaApplication(processTestConfig, utils.ProcessContext{
+		Name:        "java",
+		Cwd:         "/app",
+		CommandLine: []string{"java", "-cp", "lib/*", "org.example.Main"},
+	})
+	assert.True(t, found)
+	assert.Equal(t, javaApplication{classpath: "/app/lib/*", mainClass: "org.example.Main"}, application)
+
+	// the values of the options are not the main class
+	for _, commandLine := range [][]string{
+		{"java", "-cp", "app.jar", "--add-opens", "java.base/java.lang=ALL-UNNAMED", "com.acme.Main"},
+		{"java", "-cp", "app.jar", "-p", "mods", "--add-exports", "java.base/sun.nio.ch=ALL-UNNAMED", "com.acme.Main", "--port", "8080"},
+		{"java", "-cp", "app.jar", "@jvm.args", "-javaagent:agent.jar", "--module-path=mods", "com.acme.Main"},
+	} {
+		application, found = selectJavaApplication(processTestConfig, utils.ProcessContext{Name: "java", Cwd: "/app", CommandLine: commandLine})
+		assert.True(t, found)
+		assert.Equal(t, javaApplication{classpath: "/app/app.jar", mainClass: "com.acme.Main"}, application, commandLine)
+	}
+
+	_, found = selectJavaApplication(processTestConfig, utils.ProcessContext{
+		Name:        "java",
+		Cwd:         "/app",
+		CommandLine: []string{"java", "-version"},
+	})
+	assert.False(t, found)
+}
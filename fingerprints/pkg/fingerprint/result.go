@@ -0,0 +1,85 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// ResultVersion is the version of the JSON format of the fingerprint results
+const ResultVersion = 1
+
+// Confidence of the values of a fingerprint result.
+// When several results of the same kind are found for a container, the values with the highest confidence win.
+type Confidence string
+
+const (
+	// the values are read from a file with a defined format (for example /etc/os-release or a jar manifest)
+	ConfidenceHigh Confidence = "high"
+	// the values are derived from heuristics (for example the strings of an executable or the output of `--version`)
+	ConfidenceMedium Confidence = "medium"
+	// the values are guessed from the absence of other evidence
+	ConfidenceLow Confidence = "low"
+)
+
+// EvidenceType is the type of the source that a fingerprint result is based on
+type EvidenceType string
+
+const (
+	// a file of the container (Path is the file path)
+	EvidenceFile EvidenceType = "file"
+	// an entry of a jar manifest (Path is the jar path, Detail is the name of the entry)
+	EvidenceManifestEntry EvidenceType = "manifest-entry"
+	// an entry of a jar (Path is the jar path, Detail is the name of the entry)
+	EvidenceJarEntry EvidenceType = "jar-entry"
+	// a section of an ELF executable (Path is the executable path, Detail is the name of the section)
+	EvidenceELFSection EvidenceType = "elf-section"
+	// the strings embedded in an executable (Path is the executable path, Detail is the matched string)
+	EvidenceExecutableStrings EvidenceType = "executable-strings"
+	// the build information of a Go executable (Path is the executable path, Detail is the build setting)
+	EvidenceBuildInfo EvidenceType = "build-info"
+	// the output of a command (Path is the executable path, Detail is the arguments of the command)
+	EvidenceCommandOutput EvidenceType = "command-output"
+	// an argument of the command line of the process (Detail is the argument)
+	EvidenceCommandLine EvidenceType = "command-line"
+	// an environment variable of the process (Detail is the name of the variable)
+	EvidenceEnvironment EvidenceType = "environment"
+)
+
+// Evidence is a source that a fingerprint result is based on
+type Evidence struct {
+	Type   EvidenceType `json:"type"`
+	Path   string       `json:"path,omitempty"`
+	Detail string       `json:"detail,omitempty"`
+}
+
+// Result is the JSON document written by a fingerprint to the output directory.
+//
+// Results are written to <kind>.<fingerprint>.<unique suffix>.json files so that several fingerprints
+// (or the same fingerprint run for several processes of the container) never overwrite each other.
+// The exporter merges the results of the same kind.
+type Result struct {
+	// Version of the result format (ResultVersion)
+	Version int `json:"version"`
+	// Name of the fingerprint that produced the result
+	Fingerprint string `json:"fingerprint"`
+	// Kind of the values (for example "os", "runtime-kind" or "java-runtimes-fingerprints")
+	Kind string `json:"kind"`
+	// Detected values
+	Values map[string]string `json:"values"`
+	// Sources that the values are based on
+	Evidence []Evidence `json:"evidence,omitempty"`
+	// Confidence of the values
+	Confidence Confidence `json:"confidence"`
+}
+
+func writeResult(outputDir string, result Result) error {
+	file, err := os.CreateTemp(outputDir, result.Kind+"."+result.Fingerprint+".*.json")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,172 @@
+package fingerprint
+
+import (
+	"debug/elf"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+var (
+	// Node.js version embedded in the executable: the `node/vX.Y.Z` user agent
+	// and the URLs of the `process.release` data (`.../release/vX.Y.Z/node-vX.Y.Z-headers.tar.gz`)
+	nodeVersion = regexp.MustCompile(`(?:node/v|/release/v|node-v)([0-9]+\.[0-9]+\.[0-9]+)`)
+	// major and minor versions of Python in the name of its executable (`python3.11`) or library (`libpython3.11.so.1.0`)
+	pythonMinorVersion = regexp.MustCompile(`python([0-9]+\.[0-9]+)`)
+	// version of the `pyvenv.cfg` file of a virtual environment (`version = 3.11.4` or `version_info = 3.11.4.final.0`)
+	pyvenvVersion = regexp.MustCompile(`^([0-9]+\.[0-9]+\.[0-9]+)`)
+)
+
+// Size of the chunks of the executables scanned for the version strings
+const scanChunkSize = 1 << 20
+
+// staticVersion is a version of a runtime read from its files
+type staticVersion struct {
+	// version formatted like the output of the `--version` of the executable
+	version    string
+	confidence Confidence
+	evidence   Evidence
+}
+
+// staticVersionDetectors detect the version of the runtime kinds without running their executable.
+// The executable is the path of the executable in the root filesystem.
+var staticVersionDetectors = map[string]func(root string, executable string) (staticVersion, bool){
+	"Node.js": getNodeStaticVersion,
+	"Python":  getPythonStaticVersion,
+}
+
+// getNodeStaticVersion reads the version of Node.js from the strings embedded in the node executable
+func getNodeStaticVersion(root string, executable string) (staticVersion, bool) {
+	match, found := findInFile(root, executable, nodeVersion)
+	if !found {
+		return staticVersion{}, false
+	}
+	return staticVersion{
+		version:    "v" + match[1],
+		confidence: ConfidenceMedium,
+		evidence:   Evidence{Type: EvidenceExecutableStrings, Path: executable, Detail: match[0]},
+	}, true
+}
+
+// getPythonStaticVersion reads the version of Python from the pyvenv.cfg file of its virtual environment,
+// or from the libpython library that the executable is linked to (or from the executable if it is statically linked).
+func getPythonStaticVersion(root string, executable string) (staticVersion, bool) {
+	// <venv>/bin/python
+	pyvenvCfg := filepath.Join(filepath.Dir(filepath.Dir(executable)), "pyvenv.cfg")
+	if properties, found := utils.ReadPropertiesFile(root, pyvenvCfg); found {
+		for _, key := range []string{"version", "version_info"} {
+			if matches := pyvenvVersion.FindStringSubmatch(properties[key]); matches != nil {
+				return staticVersion{
+					version:    "Python " + matches[1],
+					confidence: ConfidenceHigh,
+					evidence:   Evidence{Type: EvidenceFile, Path: pyvenvCfg, Detail: key},
+				}, true
+			}
+		}
+	}
+
+	// the executable of a virtual environment is a symbolic link to the executable of the Python installation
+	if resolved, err := utils.EvalSymlinks(root, executable); err == nil {
+		executable = resolved
+	}
+	library, soname := findLibPython(root, executable)
+	minorVersion := ""
+	if matches := pythonMinorVersion.FindStringSubmatch(soname); matches != nil {
+		minorVersion = matches[1]
+	} else if matches := pythonMinorVersion.FindStringSubmatch(filepath.Base(executable)); matches != nil {
+		minorVersion = matches[1]
+	}
+
+	// the sys.version string of the library (or of a statically linked executable) starts with the full version
+	versionPattern := `[0-9]+\.[0-9]+`
+	if minorVersion != "" {
+		versionPattern = regexp.QuoteMeta(minorVersion)
+	}
+	sysVersion := regexp.MustCompile(`\x00(` + versionPattern + `\.[0-9]+(?:(?:a|b|rc)[0-9]+)?\+?)\x00`)
+	for _, path := range []string{library, executable} {
+		if path == "" {
+			continue
+		}
+		if match, found := findInFile(root, path, sysVersion); found {
+			return staticVersion{
+				version:    "Python " + match[1],
+				confidence: ConfidenceMedium,
+				evidence:   Evidence{Type: EvidenceExecutableStrings, Path: path, Detail: match[1]},
+			}, true
+		}
+	}
+
+	if minorVersion == "" {
+		return staticVersion{}, false
+	}
+	evidence := Evidence{Type: EvidenceFile, Path: executable}
+	if soname != "" {
+		evidence = Evidence{Type: EvidenceELFSection, Path: executable, Detail: ".dynamic " + soname}
+	}
+	return staticVersion{
+		version:    "Python " + minorVersion,
+		confidence: ConfidenceMedium,
+		evidence:   evidence,
+	}, true
+}
+
+// findLibPython returns the path (in the root filesystem) and the soname of the libpython library
+// that the executable depends on
+func findLibPython(root string, executable string) (string, string) {
+	file, err := utils.Open(root, executable)
+	if err != nil {
+		return "", ""
+	}
+	defer file.Close()
+	elfFile, err := elf.NewFile(file)
+	if err != nil {
+		return "", ""
+	}
+	defer elfFile.Close()
+	libraries, err := elfFile.ImportedLibraries()
+	if err != nil {
+		return "", ""
+	}
+	for _, soname := range libraries {
+		if strings.HasPrefix(soname, "libpython") {
+			return findLibrary(root, soname), soname
+		}
+	}
+	return "", ""
+}
+
+// findInFile returns the first match (and its submatches) of the pattern in the content of the file.
+// The file is scanned by chunks so that large executables are not read in memory.
+func findInFile(root string, path string, pattern *regexp.Regexp) ([]string, bool) {
+	file, err := utils.Open(root, path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	// the end of the previous chunk is kept to match the strings that span two chunks
+	const overlap = 256
+	buffer := make([]byte, 0, scanChunkSize+overlap)
+	chunk := make([]byte, scanChunkSize)
+	r := utils.LimitReader(path, file)
+	for {
+		n, err := io.ReadFull(r, chunk)
+		buffer = append(buffer, chunk[:n]...)
+		if matches := pattern.FindSubmatch(buffer); matches != nil {
+			found := make([]string, len(matches))
+			for i, match := range matches {
+				found[i] = string(match)
+			}
+			return found, true
+		}
+		if err != nil {
+			return nil, false
+		}
+		if len(buffer) > overlap {
+			buffer = append(buffer[:0], buffer[len(buffer)-overlap:]...)
+		}
+	}
+}
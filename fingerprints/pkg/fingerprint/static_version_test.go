@@ -0,0 +1,102 @@
+package fingerprint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRootFile(t *testing.T, root string, path string, content string) {
+	path = filepath.Join(root, path)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0755))
+}
+
+func TestGetNodeStaticVersion(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/usr/local/bin/node", "\x7fELF\x00https://nodejs.org/download/release/v20.11.1/node-v20.11.1-headers.tar.gz\x00")
+	writeRootFile(t, root, "/usr/local/bin/other", "\x7fELF\x00v20.11.1\x00")
+
+	detected, found := getNodeStaticVersion(root, "/usr/local/bin/node")
+	assert.True(t, found)
+	assert.Equal(t, "v20.11.1", detected.version)
+	assert.Equal(t, ConfidenceMedium, detected.confidence)
+	assert.Equal(t, Evidence{Type: EvidenceExecutableStrings, Path: "/usr/local/bin/node", Detail: "/release/v20.11.1"}, detected.evidence)
+
+	_, found = getNodeStaticVersion(root, "/usr/local/bin/other")
+	assert.False(t, found)
+	_, found = getNodeStaticVersion(root, "/usr/local/bin/missing")
+	assert.False(t, found)
+}
+
+func TestGetPythonStaticVersion(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/usr/bin/python3.11", "\x7fELF\x003.12.1\x00\x003.11.9\x00")
+	writeRootFile(t, root, "/usr/bin/python3.12", "\x7fELF\x00")
+	assert.NoError(t, os.Symlink("python3.11", filepath.Join(root, "usr", "bin", "python3")))
+	writeRootFile(t, root, "/app/venv/pyvenv.cfg", "home = /usr/bin\nversion_info = 3.11.4.final.0\n")
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "app", "venv", "bin"), 0755))
+	assert.NoError(t, os.Symlink("/usr/bin/python3", filepath.Join(root, "app", "venv", "bin", "python")))
+
+	// the pyvenv.cfg file of the virtual environment
+	detected, found := getPythonStaticVersion(root, "/app/venv/bin/python")
+	assert.True(t, found)
+	assert.Equal(t, "Python 3.11.4", detected.version)
+	assert.Equal(t, ConfidenceHigh, detected.confidence)
+	assert.Equal(t, Evidence{Type: EvidenceFile, Path: "/app/venv/pyvenv.cfg", Detail: "version_info"}, detected.evidence)
+
+	// the version string of the executable matching the version of its name
+	detected, found = getPythonStaticVersion(root, "/usr/bin/python3")
+	assert.True(t, found)
+	assert.Equal(t, "Python 3.11.9", detected.version)
+	assert.Equal(t, Evidence{Type: EvidenceExecutableStrings, Path: "/usr/bin/python3.11", Detail: "3.11.9"}, detected.evidence)
+
+	// only the major and minor versions are known from the name of the executable
+	detected, found = getPythonStaticVersion(root, "/usr/bin/python3.12")
+	assert.True(t, found)
+	assert.Equal(t, "Python 3.12", detected.version)
+	assert.Equal(t, ConfidenceMedium, detected.confidence)
+
+	_, found = getPythonStaticVersion(root, "/usr/bin/missing")
+	assert.False(t, found)
+}
+
+func TestFindInFile(t *testing.T) {
+	root := t.TempDir()
+	// the version string spans two chunks
+	content := strings.Repeat("\x00", scanChunkSize-10) + "node/v18.19.1\x00"
+	writeRootFile(t, root, "/node", content)
+
+	match, found := findInFile(root, "/node", nodeVersion)
+	assert.True(t, found)
+	assert.Equal(t, []string{"node/v18.19.1", "18.19.1"}, match)
+
+	_, found = findInFile(root, "/node", regexp.MustCompile("python"))
+	assert.False(t, found)
+}
+
+func TestKindExecutableStaticVersion(t *testing.T) {
+	root := t.TempDir()
+	writeRootFile(t, root, "/usr/local/bin/node", "\x7fELF\x00node/v20.11.1\x00")
+	outputDir := t.TempDir()
+	writeRootFile(t, outputDir, "config.toml", "[fingerprints]\nversion-detection = \"static\"\n"+
+		"[[fingerprints.version-executables]]\nprocess-names = [\"node\"]\nruntime-kind-name = \"Node.js\"\nversion-regex = '^v(?P<version>\\S+)'\n")
+	writeProcess(t, outputDir, "/app", map[string]string{"PATH": "/usr/bin:/usr/local/bin"}, "node", "server.js")
+
+	// the executable of the root filesystem is found in the PATH and is not run
+	assert.NoError(t, Run("kind-executable", []string{"--root", root, outputDir}))
+	files, _ := filepath.Glob(filepath.Join(outputDir, "runtime-kind.kind-executable.*.json"))
+	assert.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	var result Result
+	assert.NoError(t, json.Unmarshal(content, &result))
+	// the static version is parsed like the output of `--version`
+	assert.Equal(t, map[string]string{"runtime-kind": "Node.js", "runtime-kind-version": "20.11.1"}, result.Values)
+	assert.Equal(t, []Evidence{{Type: EvidenceExecutableStrings, Path: "/usr/local/bin/node", Detail: "node/v20.11.1"}}, result.Evidence)
+}
@@ -0,0 +1,55 @@
+package ospackages
+
+import (
+	"bufio"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// readApkInstalled reads the packages from the apk database.
+//
+// Each package is described by a paragraph of `X:value` lines (`P` for the name, `V` for the version and `A` for the architecture)
+// and paragraphs are separated by blank lines.
+func readApkInstalled(root string, path string) ([]Package, error) {
+	file, err := utils.Open(root, path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	packages := []Package{}
+	pkg := Package{}
+
+	scanner := bufio.NewScanner(utils.LimitReader(path, file))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if pkg.Name != "" {
+				packages = append(packages, pkg)
+			}
+			pkg = Package{}
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		switch key {
+		case "P":
+			pkg.Name = value
+		case "V":
+			pkg.Version = value
+		case "A":
+			pkg.Arch = value
+		}
+	}
+	if pkg.Name != "" {
+		packages = append(packages, pkg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
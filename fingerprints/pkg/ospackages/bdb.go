@@ -0,0 +1,120 @@
+package ospackages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"fingerprints/pkg/utils"
+)
+
+// Minimal reader of Berkeley DB hash databases (used by rpm < 4.16)
+// that reads all the values stored in overflow pages.
+
+const (
+	bdbHashMagic = 0x061561
+
+	bdbPageHeaderSize = 26
+
+	bdbHashUnsortedPage = 2
+	bdbOverflowPage     = 7
+	bdbHashPage         = 13
+
+	bdbHashOffPageItem = 3
+)
+
+func readBerkeleyDBHashValues(root string, path string) ([][]byte, error) {
+	content, err := utils.ReadFile(root, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < 72 {
+		return nil, fmt.Errorf("%s is not a Berkeley DB database", path)
+	}
+
+	// the database is written in the byte order of the host that created it
+	var order binary.ByteOrder = binary.LittleEndian
+	switch {
+	case binary.LittleEndian.Uint32(content[12:16]) == bdbHashMagic:
+	case binary.BigEndian.Uint32(content[12:16]) == bdbHashMagic:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%s is not a Berkeley DB hash database", path)
+	}
+	pageSize := int(order.Uint32(content[20:24]))
+	lastPage := int(order.Uint32(content[32:36]))
+	if pageSize < bdbPageHeaderSize {
+		return nil, fmt.Errorf("invalid page size %d", pageSize)
+	}
+
+	page := func(number int) ([]byte, error) {
+		start := number * pageSize
+		if number < 0 || start+pageSize > len(content) {
+			return nil, fmt.Errorf("invalid page number %d", number)
+		}
+		return content[start : start+pageSize], nil
+	}
+
+	values := [][]byte{}
+	for number := 1; number <= lastPage; number++ {
+		hashPage, err := page(number)
+		if err != nil {
+			return nil, err
+		}
+		pageType := hashPage[25]
+		if pageType != bdbHashPage && pageType != bdbHashUnsortedPage {
+			continue
+		}
+		entries := int(order.Uint16(hashPage[20:22]))
+		// items are stored as key/value pairs, only the values are read
+		for i := 1; i < entries; i += 2 {
+			indexOffset := bdbPageHeaderSize + 2*i
+			if indexOffset+2 > len(hashPage) {
+				break
+			}
+			itemOffset := int(order.Uint16(hashPage[indexOffset : indexOffset+2]))
+			if itemOffset+12 > len(hashPage) || hashPage[itemOffset] != bdbHashOffPageItem {
+				continue
+			}
+			// HOFFPAGE item: type (1 byte), unused (3 bytes), first overflow page number, total length
+			overflowPage := int(order.Uint32(hashPage[itemOffset+4 : itemOffset+8]))
+			length := int(order.Uint32(hashPage[itemOffset+8 : itemOffset+12]))
+			// the value is stored in the pages of the file
+			if length > len(content) {
+				return nil, fmt.Errorf("invalid overflow value length %d", length)
+			}
+			value, err := readBerkeleyDBOverflow(page, order, overflowPage, length)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+func readBerkeleyDBOverflow(page func(int) ([]byte, error), order binary.ByteOrder, number int, length int) ([]byte, error) {
+	value := make([]byte, 0, length)
+	visited := make(map[int]bool)
+	for number != 0 && len(value) < length {
+		if visited[number] {
+			return nil, fmt.Errorf("loop in overflow pages at page %d", number)
+		}
+		visited[number] = true
+
+		overflow, err := page(number)
+		if err != nil {
+			return nil, err
+		}
+		if overflow[25] != bdbOverflowPage {
+			return nil, fmt.Errorf("unexpected page type %d for overflow page %d", overflow[25], number)
+		}
+		// the hf_offset field of an overflow page contains the number of bytes stored in the page
+		size := min(int(order.Uint16(overflow[22:24])), len(overflow)-bdbPageHeaderSize, length-len(value))
+		value = append(value, overflow[bdbPageHeaderSize:bdbPageHeaderSize+size]...)
+		number = int(order.Uint32(overflow[16:20]))
+	}
+	if len(value) != length {
+		return nil, fmt.Errorf("truncated overflow value")
+	}
+	return value, nil
+}
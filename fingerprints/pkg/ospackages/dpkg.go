@@ -0,0 +1,64 @@
+package ospackages
+
+import (
+	"bufio"
+	"strings"
+
+	"fingerprints/pkg/utils"
+)
+
+// readDpkgStatus reads the packages from a dpkg status file.
+//
+// Each package is described by a paragraph of `Field: value` lines and paragraphs are separated by blank lines.
+// Only the packages with a `install ok installed` status are returned.
+func readDpkgStatus(root string, path string) ([]Package, error) {
+	file, err := utils.Open(root, path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	packages := []Package{}
+	pkg := Package{}
+	installed := true
+
+	add := func() {
+		if pkg.Name != "" && installed {
+			packages = append(packages, pkg)
+		}
+		pkg = Package{}
+		installed = true
+	}
+
+	scanner := bufio.NewScanner(utils.LimitReader(path, file))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			add()
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.HasPrefix(line, " ") {
+			// continuation line of a multi-line field
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			pkg.Name = value
+		case "Version":
+			pkg.Version = value
+		case "Architecture":
+			pkg.Arch = value
+		case "Status":
+			installed = strings.HasSuffix(value, " installed")
+		}
+	}
+	add()
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return packages, nil
+}
@@ -0,0 +1,73 @@
+package ospackages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"fingerprints/pkg/utils"
+)
+
+// Minimal reader of the rpm ndb database (Packages.db) that reads all the header blobs.
+//
+// The database starts with a header followed by slots pointing to the blobs.
+// All the integers are little-endian.
+
+const (
+	ndbHeaderMagic = 'R' | 'p'<<8 | 'm'<<16 | 'P'<<24
+	ndbSlotMagic   = 'S' | 'l'<<8 | 'o'<<16 | 't'<<24
+	ndbBlobMagic   = 'B' | 'l'<<8 | 'b'<<16 | 'S'<<24
+
+	ndbPageSize       = 4096
+	ndbSlotSize       = 16
+	ndbBlockSize      = 16
+	ndbBlobHeaderSize = 16
+	// the database header uses the space of the first 2 slots
+	ndbHeaderSlots = 2
+)
+
+func readNdbBlobs(root string, path string) ([][]byte, error) {
+	content, err := utils.ReadFile(root, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < ndbHeaderSlots*ndbSlotSize || binary.LittleEndian.Uint32(content[0:4]) != ndbHeaderMagic {
+		return nil, fmt.Errorf("%s is not a rpm ndb database", path)
+	}
+	// header: magic, version, generation, number of pages of slots
+	slotPages := int(binary.LittleEndian.Uint32(content[12:16]))
+	slotCount := slotPages*ndbPageSize/ndbSlotSize - ndbHeaderSlots
+
+	blobs := [][]byte{}
+	for i := 0; i < slotCount; i++ {
+		slotOffset := (ndbHeaderSlots + i) * ndbSlotSize
+		if slotOffset+ndbSlotSize > len(content) {
+			break
+		}
+		slot := content[slotOffset : slotOffset+ndbSlotSize]
+		// slot: magic, package index, block offset, block count
+		if binary.LittleEndian.Uint32(slot[0:4]) != ndbSlotMagic {
+			return nil, fmt.Errorf("invalid slot %d", i)
+		}
+		pkgIndex := binary.LittleEndian.Uint32(slot[4:8])
+		if pkgIndex == 0 {
+			// empty slot
+			continue
+		}
+		blobOffset := int(binary.LittleEndian.Uint32(slot[8:12])) * ndbBlockSize
+		if blobOffset+ndbBlobHeaderSize > len(content) {
+			return nil, fmt.Errorf("invalid blob offset for slot %d", i)
+		}
+		// blob header: magic, package index, generation, length
+		blobHeader := content[blobOffset : blobOffset+ndbBlobHeaderSize]
+		if binary.LittleEndian.Uint32(blobHeader[0:4]) != ndbBlobMagic || binary.LittleEndian.Uint32(blobHeader[4:8]) != pkgIndex {
+			return nil, fmt.Errorf("invalid blob for package %d", pkgIndex)
+		}
+		length := int(binary.LittleEndian.Uint32(blobHeader[12:16]))
+		start := blobOffset + ndbBlobHeaderSize
+		if start+length > len(content) {
+			return nil, fmt.Errorf("truncated blob for package %d", pkgIndex)
+		}
+		blobs = append(blobs, content[start:start+length])
+	}
+	return blobs, nil
+}
@@ -0,0 +1,89 @@
+// Package ospackages reads the inventory of the packages installed by the package manager
+// of the Operating System (rpm, dpkg or apk).
+package ospackages
+
+import (
+	"path"
+	"path/filepath"
+
+	"fingerprints/pkg/utils"
+)
+
+type Package struct {
+	Name    string
+	Version string
+	Arch    string
+}
+
+// database is a package database and the function that reads it
+type database struct {
+	path string
+	read func(root string, path string) ([]Package, error)
+}
+
+var databases = []database{
+	// rpm >= 4.16 (RHEL 9, Fedora 33+)
+	{"/var/lib/rpm/rpmdb.sqlite", readRpmSQLite},
+	{"/usr/lib/sysimage/rpm/rpmdb.sqlite", readRpmSQLite},
+	// rpm ndb (SUSE)
+	{"/var/lib/rpm/Packages.db", readRpmNdb},
+	{"/usr/lib/sysimage/rpm/Packages.db", readRpmNdb},
+	// rpm Berkeley DB (RHEL 7 & 8)
+	{"/var/lib/rpm/Packages", readRpmBerkeleyDB},
+	{"/var/lib/dpkg/status", readDpkgStatus},
+	// distroless images
+	{"/var/lib/dpkg/status.d", readDpkgStatusDir},
+	{"/lib/apk/db/installed", readApkInstalled},
+}
+
+// GetInstalledPackages returns the packages from the first package database that is found
+// whose name matches one of the patterns of the allow list, and the path of that database.
+//
+// The patterns of the allow list use the syntax of path.Match (for example `libssl*`).
+// The databases are read from the root filesystem (see utils.ResolvePath).
+func GetInstalledPackages(root string, allowList []string) ([]Package, string, error) {
+	for _, db := range databases {
+		if _, err := utils.Stat(root, db.path); err != nil {
+			continue
+		}
+		packages, err := db.read(root, db.path)
+		if err != nil {
+			return nil, db.path, err
+		}
+		return filterPackages(packages, allowList), db.path, nil
+	}
+	return nil, "", nil
+}
+
+func filterPackages(packages []Package, allowList []string) []Package {
+	filtered := []Package{}
+	for _, pkg := range packages {
+		for _, pattern := range allowList {
+			if matched, _ := path.Match(pattern, pkg.Name); matched {
+				filtered = append(filtered, pkg)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func readDpkgStatusDir(root string, dir string) ([]Package, error) {
+	entries, err := utils.ReadDir(root, dir)
+	if err != nil {
+		return nil, err
+	}
+	packages := []Package{}
+	for _, entry := range entries {
+		// symbolic links are ignored so that the files read are always in the directory
+		if !entry.Type().IsRegular() || filepath.Ext(entry.Name()) == ".md5sums" {
+			continue
+		}
+		dirPackages, err := readDpkgStatus(root, filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		packages = append(packages, dirPackages...)
+	}
+	return packages, nil
+}
@@ -0,0 +1,252 @@
+package ospackages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadRpmSQLite(t *testing.T) {
+	packages, err := readRpmSQLite("", "testdata/rpmdb.sqlite")
+	assert.NoError(t, err)
+	// 44 packages minus gpg-pubkey
+	assert.Equal(t, 43, len(packages))
+
+	filtered := filterPackages(packages, []string{"openssl*", "glibc", "zlib"})
+	assert.Equal(t, []Package{
+		{Name: "openssl-libs", Version: "1:3.0.7-27.el9", Arch: "x86_64"},
+		{Name: "glibc", Version: "2.34-100.el9", Arch: "x86_64"},
+		{Name: "zlib", Version: "1.2.11-40.el9", Arch: "x86_64"},
+	}, filtered)
+}
+
+func TestReadInvalidSQLite(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/rpmdb.sqlite")
+	assert.NoError(t, err)
+	tests := []struct {
+		name   string
+		modify func(content []byte) []byte
+		err    string
+	}{
+		{"zero page size", func(content []byte) []byte {
+			binary.BigEndian.PutUint16(content[16:18], 0)
+			return content
+		}, "invalid page size 0"},
+		{"tiny page size", func(content []byte) []byte {
+			binary.BigEndian.PutUint16(content[16:18], 4)
+			return content
+		}, "invalid page size 4"},
+		{"page size not a power of two", func(content []byte) []byte {
+			binary.BigEndian.PutUint16(content[16:18], 1000)
+			return content
+		}, "invalid page size 1000"},
+		{"reserved space", func(content []byte) []byte {
+			binary.BigEndian.PutUint16(content[16:18], 512)
+			content[20] = 100
+			return content
+		}, "invalid usable page size 412"},
+		{"payload size larger than the file", func(content []byte) []byte {
+			// a single leaf cell on the first page with a 9-byte varint payload size
+			page := content[:512]
+			clear(page[sqliteHeaderSize:])
+			page[sqliteHeaderSize] = sqliteLeafTablePage
+			binary.BigEndian.PutUint16(page[sqliteHeaderSize+3:], 1)
+			binary.BigEndian.PutUint16(page[sqliteHeaderSize+8:], 300)
+			copy(page[300:], []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+			return content
+		}, "invalid cell payload size"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rpmdb.sqlite")
+			assert.NoError(t, os.WriteFile(path, test.modify(bytes.Clone(fixture)), 0644))
+			_, err := readRpmSQLite("", path)
+			assert.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func FuzzDecodeSQLiteRecord(f *testing.F) {
+	// header of 4 bytes: a NULL, a 1-byte integer and a 3-byte string
+	f.Add([]byte{4, 0, 1, 19, 42, 'a', 'b', 'c'})
+	f.Add([]byte{2, 7, 0x40, 0x09, 0x21, 0xfb, 0x54, 0x44, 0x2d, 0x18})
+	f.Add([]byte("\xe9\xb4\xff\xd7\xff\xd7ט0"))
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		record, err := decodeSQLiteRecord(payload)
+		if err != nil {
+			return
+		}
+		for _, value := range record {
+			switch value.(type) {
+			case nil, int64, float64, []byte, string:
+			default:
+				t.Fatalf("unexpected value %#v", value)
+			}
+		}
+	})
+}
+
+func TestReadRpmBerkeleyDBFile(t *testing.T) {
+	// written by libdb (see testdata/make-bdb-packages.py)
+	packages, err := readRpmBerkeleyDB("", "testdata/Packages")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []Package{
+		{Name: "openssl-libs", Version: "1:1.1.1k-12.el8_9", Arch: "x86_64"},
+		{Name: "glibc", Version: "2.28-236.el8_9.13", Arch: "x86_64"},
+		{Name: "zlib", Version: "1.2.11-25.el8", Arch: "x86_64"},
+	}, packages)
+}
+
+func TestReadRpmBerkeleyDB(t *testing.T) {
+	pageSize := 64
+	blob := rpmHeader(map[uint32]string{rpmTagName: "openssl", rpmTagVersion: "1.1.1k", rpmTagRelease: "12.el8_9", rpmTagArch: "x86_64"})
+
+	// page 0: metadata, page 1: hash page, pages 2...: overflow pages
+	overflowPages := (len(blob) + pageSize - bdbPageHeaderSize - 1) / (pageSize - bdbPageHeaderSize)
+	content := make([]byte, (2+overflowPages)*pageSize)
+	binary.LittleEndian.PutUint32(content[12:16], bdbHashMagic)
+	binary.LittleEndian.PutUint32(content[20:24], uint32(pageSize))
+	binary.LittleEndian.PutUint32(content[32:36], uint32(1+overflowPages))
+
+	hashPage := content[pageSize : 2*pageSize]
+	hashPage[25] = bdbHashPage
+	binary.LittleEndian.PutUint16(hashPage[20:22], 2)
+	binary.LittleEndian.PutUint16(hashPage[bdbPageHeaderSize:], uint16(pageSize-16))
+	binary.LittleEndian.PutUint16(hashPage[bdbPageHeaderSize+2:], uint16(pageSize-12))
+	offPageItem := hashPage[pageSize-12:]
+	offPageItem[0] = bdbHashOffPageItem
+	binary.LittleEndian.PutUint32(offPageItem[4:8], 2)
+	binary.LittleEndian.PutUint32(offPageItem[8:12], uint32(len(blob)))
+
+	remaining := blob
+	for i := 0; i < overflowPages; i++ {
+		overflow := content[(2+i)*pageSize : (3+i)*pageSize]
+		overflow[25] = bdbOverflowPage
+		if i < overflowPages-1 {
+			binary.LittleEndian.PutUint32(overflow[16:20], uint32(3+i))
+		}
+		size := copy(overflow[bdbPageHeaderSize:], remaining)
+		binary.LittleEndian.PutUint16(overflow[22:24], uint16(size))
+		remaining = remaining[size:]
+	}
+
+	path := filepath.Join(t.TempDir(), "Packages")
+	assert.NoError(t, os.WriteFile(path, content, 0644))
+
+	packages, err := readRpmBerkeleyDB("", path)
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{{Name: "openssl", Version: "1.1.1k-12.el8_9", Arch: "x86_64"}}, packages)
+
+	// a value length larger than the file
+	binary.LittleEndian.PutUint32(offPageItem[8:12], 0xffffffff)
+	assert.NoError(t, os.WriteFile(path, content, 0644))
+	_, err = readRpmBerkeleyDB("", path)
+	assert.EqualError(t, err, "invalid overflow value length 4294967295")
+}
+
+func TestReadRpmNdb(t *testing.T) {
+	blob := rpmHeader(map[uint32]string{rpmTagName: "libopenssl3", rpmTagVersion: "3.1.4", rpmTagRelease: "150600.5.7.1", rpmTagArch: "x86_64"})
+
+	content := make([]byte, ndbPageSize+ndbBlobHeaderSize+len(blob))
+	binary.LittleEndian.PutUint32(content[0:4], ndbHeaderMagic)
+	binary.LittleEndian.PutUint32(content[12:16], 1)
+	for i := 0; i < ndbPageSize/ndbSlotSize-ndbHeaderSlots; i++ {
+		binary.LittleEndian.PutUint32(content[(ndbHeaderSlots+i)*ndbSlotSize:], ndbSlotMagic)
+	}
+	slot := content[ndbHeaderSlots*ndbSlotSize:]
+	binary.LittleEndian.PutUint32(slot[4:8], 1)
+	binary.LittleEndian.PutUint32(slot[8:12], ndbPageSize/ndbBlockSize)
+	blobHeader := content[ndbPageSize:]
+	binary.LittleEndian.PutUint32(blobHeader[0:4], ndbBlobMagic)
+	binary.LittleEndian.PutUint32(blobHeader[4:8], 1)
+	binary.LittleEndian.PutUint32(blobHeader[12:16], uint32(len(blob)))
+	copy(content[ndbPageSize+ndbBlobHeaderSize:], blob)
+
+	path := filepath.Join(t.TempDir(), "Packages.db")
+	assert.NoError(t, os.WriteFile(path, content, 0644))
+
+	packages, err := readRpmNdb("", path)
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{{Name: "libopenssl3", Version: "3.1.4-150600.5.7.1", Arch: "x86_64"}}, packages)
+}
+
+func TestReadDpkgStatus(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "var/lib/dpkg"), 0755))
+	// the status file is a symbolic link resolved in the root filesystem
+	assert.NoError(t, os.Symlink("/var/lib/dpkg/status-old", filepath.Join(root, "var/lib/dpkg/status")))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "var/lib/dpkg/status-old"), []byte(`Package: libssl3
+Status: install ok installed
+Architecture: amd64
+Version: 3.0.11-1~deb12u2
+Description: Secure Sockets Layer toolkit
+ This package is part of the OpenSSL project's implementation.
+
+Package: curl
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 7.88.1-10+deb12u5
+
+Package: zlib1g
+Status: install ok installed
+Architecture: amd64
+Version: 1:1.2.13.dfsg-1
+`), 0644))
+
+	packages, err := readDpkgStatus(root, "/var/lib/dpkg/status")
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "libssl3", Version: "3.0.11-1~deb12u2", Arch: "amd64"},
+		{Name: "zlib1g", Version: "1:1.2.13.dfsg-1", Arch: "amd64"},
+	}, packages)
+}
+
+func TestReadApkInstalled(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "lib/apk/db"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "lib/apk/db/installed"), []byte(`C:Q1abc=
+P:musl
+V:1.2.5-r0
+A:x86_64
+
+P:libssl3
+V:3.3.1-r3
+A:x86_64
+`), 0644))
+
+	packages, err := readApkInstalled(root, "/lib/apk/db/installed")
+	assert.NoError(t, err)
+	assert.Equal(t, []Package{
+		{Name: "musl", Version: "1.2.5-r0", Arch: "x86_64"},
+		{Name: "libssl3", Version: "3.3.1-r3", Arch: "x86_64"},
+	}, packages)
+}
+
+func TestParseInvalidRpmHeader(t *testing.T) {
+	_, err := parseRpmHeader([]byte{0, 0, 0, 1})
+	assert.Error(t, err)
+	_, err = parseRpmHeader([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 1})
+	assert.Error(t, err)
+}
+
+func rpmHeader(tags map[uint32]string) []byte {
+	index := []byte{}
+	data := []byte{}
+	for tag, value := range tags {
+		entry := make([]byte, rpmIndexEntrySize)
+		binary.BigEndian.PutUint32(entry[0:4], tag)
+		binary.BigEndian.PutUint32(entry[4:8], rpmTypeString)
+		binary.BigEndian.PutUint32(entry[8:12], uint32(len(data)))
+		binary.BigEndian.PutUint32(entry[12:16], 1)
+		index = append(index, entry...)
+		data = append(data, append([]byte(value), 0)...)
+	}
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(tags)))
+	binary.BigEndian.PutUint32(header[4:8], uint32(len(data)))
+	return append(append(header, index...), data...)
+}
@@ -0,0 +1,124 @@
+package ospackages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+const (
+	rpmTagName    = 1000
+	rpmTagVersion = 1001
+	rpmTagRelease = 1002
+	rpmTagEpoch   = 1003
+	rpmTagArch    = 1022
+
+	rpmTypeInt32  = 4
+	rpmTypeString = 6
+
+	rpmIndexEntrySize = 16
+)
+
+// parseRpmHeader returns the package described by a rpm header blob as it is stored in the rpm database.
+//
+// The blob is composed of the number of index entries (int32), the size of the data store (int32),
+// the index entries (tag, type, offset, count as int32) and the data store.
+// All the integers are big-endian.
+func parseRpmHeader(blob []byte) (Package, error) {
+	if len(blob) < 8 {
+		return Package{}, fmt.Errorf("rpm header is too short")
+	}
+	indexCount := int(binary.BigEndian.Uint32(blob[0:4]))
+	dataLength := int(binary.BigEndian.Uint32(blob[4:8]))
+	dataStart := 8 + indexCount*rpmIndexEntrySize
+	if indexCount < 0 || dataLength < 0 || dataStart+dataLength > len(blob) || dataStart < 8 {
+		return Package{}, fmt.Errorf("invalid rpm header: %d index entries, %d bytes of data", indexCount, dataLength)
+	}
+	data := blob[dataStart : dataStart+dataLength]
+
+	pkg := Package{}
+	release := ""
+	epoch := ""
+	for i := 0; i < indexCount; i++ {
+		entry := blob[8+i*rpmIndexEntrySize : 8+(i+1)*rpmIndexEntrySize]
+		tag := binary.BigEndian.Uint32(entry[0:4])
+		tagType := binary.BigEndian.Uint32(entry[4:8])
+		offset := int(binary.BigEndian.Uint32(entry[8:12]))
+		if offset < 0 || offset >= len(data) {
+			continue
+		}
+		switch {
+		case tagType == rpmTypeString && tag == rpmTagName:
+			pkg.Name = readRpmString(data[offset:])
+		case tagType == rpmTypeString && tag == rpmTagVersion:
+			pkg.Version = readRpmString(data[offset:])
+		case tagType == rpmTypeString && tag == rpmTagRelease:
+			release = readRpmString(data[offset:])
+		case tagType == rpmTypeString && tag == rpmTagArch:
+			pkg.Arch = readRpmString(data[offset:])
+		case tagType == rpmTypeInt32 && tag == rpmTagEpoch && offset+4 <= len(data):
+			epoch = strconv.FormatUint(uint64(binary.BigEndian.Uint32(data[offset:offset+4])), 10)
+		}
+	}
+	if pkg.Name == "" {
+		return Package{}, fmt.Errorf("rpm header has no name")
+	}
+	if release != "" {
+		pkg.Version = pkg.Version + "-" + release
+	}
+	if epoch != "" && epoch != "0" {
+		pkg.Version = epoch + ":" + pkg.Version
+	}
+	return pkg, nil
+}
+
+func readRpmString(data []byte) string {
+	if end := bytes.IndexByte(data, 0); end != -1 {
+		return string(data[:end])
+	}
+	return string(data)
+}
+
+func parseRpmHeaders(blobs [][]byte) []Package {
+	packages := []Package{}
+	for _, blob := range blobs {
+		if pkg, err := parseRpmHeader(blob); err == nil && pkg.Name != "gpg-pubkey" {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+func readRpmSQLite(root string, path string) ([]Package, error) {
+	// CREATE TABLE 'Packages' (hnum INTEGER PRIMARY KEY AUTOINCREMENT, blob BLOB NOT NULL)
+	records, err := readSQLiteTable(root, path, "Packages")
+	if err != nil {
+		return nil, err
+	}
+	blobs := [][]byte{}
+	for _, record := range records {
+		if len(record) > 1 {
+			if blob, ok := record[1].([]byte); ok {
+				blobs = append(blobs, blob)
+			}
+		}
+	}
+	return parseRpmHeaders(blobs), nil
+}
+
+func readRpmBerkeleyDB(root string, path string) ([]Package, error) {
+	blobs, err := readBerkeleyDBHashValues(root, path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRpmHeaders(blobs), nil
+}
+
+func readRpmNdb(root string, path string) ([]Package, error) {
+	blobs, err := readNdbBlobs(root, path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRpmHeaders(blobs), nil
+}
@@ -0,0 +1,294 @@
+package ospackages
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"fingerprints/pkg/utils"
+)
+
+// Minimal reader of SQLite database files (https://www.sqlite.org/fileformat.html)
+// that reads all the records of a table by walking its b-tree.
+
+const (
+	sqliteHeaderMagic = "SQLite format 3\x00"
+	sqliteHeaderSize  = 100
+
+	sqliteInteriorTablePage = 0x05
+	sqliteLeafTablePage     = 0x0d
+)
+
+type sqliteFile struct {
+	content    []byte
+	pageSize   int
+	usableSize int
+}
+
+// readSQLiteTable returns the records of the table.
+// Each column of a record is nil, an int64, a float64, a string or a []byte.
+func readSQLiteTable(root string, path string, table string) ([][]any, error) {
+	content, err := utils.ReadFile(root, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) < sqliteHeaderSize || string(content[:16]) != sqliteHeaderMagic {
+		return nil, fmt.Errorf("%s is not a SQLite database", path)
+	}
+	pageSize := int(binary.BigEndian.Uint16(content[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	// the page size is a power of two between 512 and 65536
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid page size %d in %s", pageSize, path)
+	}
+	db := sqliteFile{
+		content:    content,
+		pageSize:   pageSize,
+		usableSize: pageSize - int(content[20]),
+	}
+	if db.usableSize < 480 {
+		return nil, fmt.Errorf("invalid usable page size %d in %s", db.usableSize, path)
+	}
+
+	// the schema table is stored in the first page
+	// CREATE TABLE sqlite_schema(type text, name text, tbl_name text, rootpage integer, sql text)
+	schema, err := db.readTable(1)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range schema {
+		if len(record) < 4 || record[0] != "table" || record[1] != table {
+			continue
+		}
+		rootPage, ok := record[3].(int64)
+		if !ok {
+			return nil, fmt.Errorf("invalid root page for table %s", table)
+		}
+		return db.readTable(int(rootPage))
+	}
+	return nil, fmt.Errorf("table %s not found in %s", table, path)
+}
+
+func (db *sqliteFile) page(number int) ([]byte, error) {
+	start := (number - 1) * db.pageSize
+	if number < 1 || start+db.pageSize > len(db.content) {
+		return nil, fmt.Errorf("invalid page number %d", number)
+	}
+	return db.content[start : start+db.pageSize], nil
+}
+
+func (db *sqliteFile) readTable(rootPage int) ([][]any, error) {
+	records := [][]any{}
+	pages := []int{rootPage}
+	visited := make(map[int]bool)
+	for len(pages) > 0 {
+		number := pages[0]
+		pages = pages[1:]
+		if visited[number] {
+			return nil, fmt.Errorf("loop in b-tree at page %d", number)
+		}
+		visited[number] = true
+
+		page, err := db.page(number)
+		if err != nil {
+			return nil, err
+		}
+		headerOffset := 0
+		if number == 1 {
+			headerOffset = sqliteHeaderSize
+		}
+		if headerOffset+12 > len(page) {
+			return nil, fmt.Errorf("invalid page %d", number)
+		}
+		pageType := page[headerOffset]
+		cellCount := int(binary.BigEndian.Uint16(page[headerOffset+3 : headerOffset+5]))
+		switch pageType {
+		case sqliteInteriorTablePage:
+			cellPointers := headerOffset + 12
+			for i := 0; i < cellCount; i++ {
+				cellOffset, err := cellPointer(page, cellPointers, i)
+				if err != nil {
+					return nil, err
+				}
+				pages = append(pages, int(binary.BigEndian.Uint32(page[cellOffset:cellOffset+4])))
+			}
+			pages = append(pages, int(binary.BigEndian.Uint32(page[headerOffset+8:headerOffset+12])))
+		case sqliteLeafTablePage:
+			cellPointers := headerOffset + 8
+			for i := 0; i < cellCount; i++ {
+				cellOffset, err := cellPointer(page, cellPointers, i)
+				if err != nil {
+					return nil, err
+				}
+				payload, err := db.readLeafCellPayload(page, cellOffset)
+				if err != nil {
+					return nil, err
+				}
+				record, err := decodeSQLiteRecord(payload)
+				if err != nil {
+					return nil, err
+				}
+				records = append(records, record)
+			}
+		default:
+			return nil, fmt.Errorf("unexpected page type %d for table page %d", pageType, number)
+		}
+	}
+	return records, nil
+}
+
+func cellPointer(page []byte, cellPointers int, i int) (int, error) {
+	if cellPointers+2*i+2 > len(page) {
+		return 0, fmt.Errorf("invalid cell pointer %d", i)
+	}
+	cellOffset := int(binary.BigEndian.Uint16(page[cellPointers+2*i : cellPointers+2*i+2]))
+	if cellOffset+4 > len(page) {
+		return 0, fmt.Errorf("invalid cell offset %d", cellOffset)
+	}
+	return cellOffset, nil
+}
+
+// readLeafCellPayload returns the payload of a table leaf cell, including the content of its overflow pages
+func (db *sqliteFile) readLeafCellPayload(page []byte, cellOffset int) ([]byte, error) {
+	payloadSize, n := readVarint(page[cellOffset:])
+	cellOffset += n
+	// the payload is stored in the pages of the file
+	if payloadSize > uint64(len(db.content)) {
+		return nil, fmt.Errorf("invalid cell payload size %d", payloadSize)
+	}
+	// skip the rowid
+	_, n = readVarint(page[cellOffset:])
+	cellOffset += n
+
+	localSize := db.localPayloadSize(int(payloadSize))
+	if cellOffset+localSize > len(page) {
+		return nil, fmt.Errorf("invalid cell payload size %d", payloadSize)
+	}
+	payload := make([]byte, 0, payloadSize)
+	payload = append(payload, page[cellOffset:cellOffset+localSize]...)
+	if localSize == int(payloadSize) {
+		return payload, nil
+	}
+
+	if cellOffset+localSize+4 > len(page) {
+		return nil, fmt.Errorf("invalid overflow page pointer")
+	}
+	overflowPage := int(binary.BigEndian.Uint32(page[cellOffset+localSize : cellOffset+localSize+4]))
+	for overflowPage != 0 && len(payload) < int(payloadSize) {
+		overflow, err := db.page(overflowPage)
+		if err != nil {
+			return nil, err
+		}
+		size := min(int(payloadSize)-len(payload), db.usableSize-4)
+		payload = append(payload, overflow[4:4+size]...)
+		overflowPage = int(binary.BigEndian.Uint32(overflow[0:4]))
+	}
+	if len(payload) != int(payloadSize) {
+		return nil, fmt.Errorf("truncated cell payload")
+	}
+	return payload, nil
+}
+
+// localPayloadSize returns the number of bytes of the payload that are stored in the b-tree leaf page
+func (db *sqliteFile) localPayloadSize(payloadSize int) int {
+	maxLocal := db.usableSize - 35
+	if payloadSize <= maxLocal {
+		return payloadSize
+	}
+	minLocal := ((db.usableSize-12)*32)/255 - 23
+	localSize := minLocal + ((payloadSize - minLocal) % (db.usableSize - 4))
+	if localSize > maxLocal {
+		return minLocal
+	}
+	return localSize
+}
+
+func decodeSQLiteRecord(payload []byte) ([]any, error) {
+	headerSize, n := readVarint(payload)
+	if n == 0 || headerSize > uint64(len(payload)) {
+		return nil, fmt.Errorf("invalid record header")
+	}
+	serialTypes := []uint64{}
+	for offset := uint64(n); offset < headerSize; {
+		serialType, n := readVarint(payload[offset:headerSize])
+		if n == 0 {
+			return nil, fmt.Errorf("invalid record header")
+		}
+		serialTypes = append(serialTypes, serialType)
+		offset += uint64(n)
+	}
+
+	record := make([]any, 0, len(serialTypes))
+	body := payload[headerSize:]
+	for _, serialType := range serialTypes {
+		size := serialTypeSize(serialType)
+		// the sizes of the blobs and strings come from the file and may not fit in an int
+		if size > uint64(len(body)) {
+			return nil, fmt.Errorf("invalid record body")
+		}
+		value := body[:size]
+		body = body[size:]
+		switch {
+		case serialType == 0:
+			record = append(record, nil)
+		case serialType >= 1 && serialType <= 6:
+			// big-endian two's complement integers
+			var i int64
+			if value[0]&0x80 != 0 {
+				i = -1
+			}
+			for _, b := range value {
+				i = i<<8 | int64(b)
+			}
+			record = append(record, i)
+		case serialType == 7:
+			record = append(record, math.Float64frombits(binary.BigEndian.Uint64(value)))
+		case serialType == 8:
+			record = append(record, int64(0))
+		case serialType == 9:
+			record = append(record, int64(1))
+		case serialType >= 12 && serialType%2 == 0:
+			record = append(record, value)
+		case serialType >= 13:
+			record = append(record, string(value))
+		default:
+			return nil, fmt.Errorf("unsupported serial type %d", serialType)
+		}
+	}
+	return record, nil
+}
+
+func serialTypeSize(serialType uint64) uint64 {
+	switch serialType {
+	case 0, 8, 9:
+		return 0
+	case 1, 2, 3, 4:
+		return serialType
+	case 5:
+		return 6
+	case 6, 7:
+		return 8
+	}
+	if serialType >= 12 {
+		return (serialType - 12) / 2
+	}
+	return 0
+}
+
+// readVarint reads a SQLite variable-length integer and returns its value and its size.
+// The size is 0 if the buffer does not contain a valid varint.
+func readVarint(buf []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < 9 && i < len(buf); i++ {
+		if i == 8 {
+			return value<<8 | uint64(buf[i]), 9
+		}
+		value = value<<7 | uint64(buf[i]&0x7f)
+		if buf[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
@@ -0,0 +1,70 @@
+#!/usr/bin/env python3
+# Writes the Packages fixture: a rpm < 4.16 database (Berkeley DB hash) written by libdb 5.3 (the library used by rpm)
+# through its ndbm interface, with the header blobs of a few packages of an UBI 8 image.
+import ctypes
+import os
+import struct
+import sys
+
+RPM_INT32, RPM_STRING, RPM_I18NSTRING = 4, 6, 9
+
+
+def header(tags):
+    index, data = b"", b""
+    for tag, tag_type, value in tags:
+        if tag_type == RPM_INT32:
+            data += b"\0" * (-len(data) % 4)
+            encoded = struct.pack(">I", value)
+        else:
+            encoded = value.encode() + b"\0"
+        index += struct.pack(">IIII", tag, tag_type, len(data), 1)
+        data += encoded
+    return struct.pack(">II", len(tags), len(data)) + index + data
+
+
+def package(name, epoch, version, release, arch, summary):
+    tags = [(1000, RPM_STRING, name)]
+    if epoch is not None:
+        tags.append((1003, RPM_INT32, epoch))
+    tags += [
+        (1001, RPM_STRING, version),
+        (1002, RPM_STRING, release),
+        (1004, RPM_I18NSTRING, summary),
+        # the description and the file lists make the real headers larger than a page
+        (1005, RPM_I18NSTRING, (summary + ". ") * 120),
+        (1022, RPM_STRING, arch),
+    ]
+    return header(tags)
+
+
+PACKAGES = [
+    package("openssl-libs", 1, "1.1.1k", "12.el8_9", "x86_64", "A general purpose cryptography library with TLS implementation"),
+    package("glibc", None, "2.28", "236.el8_9.13", "x86_64", "The GNU libc libraries"),
+    package("zlib", None, "1.2.11", "25.el8", "x86_64", "Compression and decompression library"),
+    package("gpg-pubkey", None, "fd431d51", "4ae0493b", "(none)", "gpg(Red Hat, Inc. (release key 2) <security@redhat.com>)"),
+]
+
+
+class Datum(ctypes.Structure):
+    _fields_ = [("dptr", ctypes.c_char_p), ("dsize", ctypes.c_int)]
+
+
+def main(path):
+    libdb = ctypes.CDLL("libdb-5.3.so")
+    libdb.__db_ndbm_open.restype = ctypes.c_void_p
+    libdb.__db_ndbm_store.argtypes = [ctypes.c_void_p, Datum, Datum, ctypes.c_int]
+    libdb.__db_ndbm_close.argtypes = [ctypes.c_void_p]
+    # the ndbm interface adds the .db extension
+    db = libdb.__db_ndbm_open(path.encode(), os.O_RDWR | os.O_CREAT | os.O_TRUNC, 0o644)
+    if not db:
+        sys.exit("unable to create " + path)
+    for number, blob in enumerate(PACKAGES, start=1):
+        key = struct.pack("<I", number)
+        if libdb.__db_ndbm_store(db, Datum(key, len(key)), Datum(blob, len(blob)), 1) != 0:
+            sys.exit("unable to store package %d" % number)
+    libdb.__db_ndbm_close(db)
+    os.rename(path + ".db", path)
+
+
+if __name__ == "__main__":
+    main(sys.argv[1] if len(sys.argv) > 1 else "Packages")
@@ -0,0 +1,236 @@
+package utils
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Names of the budgets (as in the [fingerprints.budgets] configuration)
+const (
+	BudgetTimeout               = "timeout"
+	BudgetMaxFileBytes          = "max-file-bytes"
+	BudgetMaxZipEntries         = "max-zip-entries"
+	BudgetMaxNestedJarDepth     = "max-nested-jar-depth"
+	BudgetMaxDecompressionRatio = "max-decompression-ratio"
+)
+
+// Budgets limit the resources used by a fingerprint so that a hostile (or simply huge) container can not stall a scan.
+// A budget that is not set in the configuration takes its default value (see DefaultBudgets).
+type Budgets struct {
+	// Wall-clock duration of a fingerprint (for example "30s")
+	Timeout time.Duration `toml:"timeout"`
+	// Maximum number of bytes read from a file (or from a zip entry)
+	MaxFileBytes int64 `toml:"max-file-bytes"`
+	// Maximum number of entries of a zip file (jar, war...)
+	MaxZipEntries int `toml:"max-zip-entries"`
+	// Maximum depth of the jars read inside other jars (1 for the jars packaged in a jar of the filesystem)
+	MaxNestedJarDepth int `toml:"max-nested-jar-depth"`
+	// Maximum ratio between the uncompressed and the compressed sizes of a zip entry
+	MaxDecompressionRatio int64 `toml:"max-decompression-ratio"`
+}
+
+// DefaultBudgets are the budgets of the fingerprints when they are not configured
+var DefaultBudgets = Budgets{
+	Timeout:               30 * time.Second,
+	MaxFileBytes:          256 << 20,
+	MaxZipEntries:         100000,
+	MaxNestedJarDepth:     1,
+	MaxDecompressionRatio: 100,
+}
+
+// WithDefaults returns the budgets where the budgets that are not set take their default value
+func (budgets Budgets) WithDefaults() Budgets {
+	if budgets.Timeout <= 0 {
+		budgets.Timeout = DefaultBudgets.Timeout
+	}
+	if budgets.MaxFileBytes <= 0 {
+		budgets.MaxFileBytes = DefaultBudgets.MaxFileBytes
+	}
+	if budgets.MaxZipEntries <= 0 {
+		budgets.MaxZipEntries = DefaultBudgets.MaxZipEntries
+	}
+	if budgets.MaxNestedJarDepth <= 0 {
+		budgets.MaxNestedJarDepth = DefaultBudgets.MaxNestedJarDepth
+	}
+	if budgets.MaxDecompressionRatio <= 0 {
+		budgets.MaxDecompressionRatio = DefaultBudgets.MaxDecompressionRatio
+	}
+	return budgets
+}
+
+// BudgetExceededError is returned by the helpers of the utils package when a budget of the running fingerprint is exceeded
+type BudgetExceededError struct {
+	// name of the budget (BudgetTimeout, BudgetMaxFileBytes...)
+	Budget string
+	// value of the budget
+	Limit string
+	// path of the file that exceeds the budget (empty for the timeout)
+	Path string
+}
+
+func (e *BudgetExceededError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("budget exceeded: %s (limit %s)", e.Budget, e.Limit)
+	}
+	return fmt.Sprintf("budget exceeded: %s of %s (limit %s)", e.Budget, e.Path, e.Limit)
+}
+
+// BudgetUsage is the usage of the budgets by the running fingerprint
+type BudgetUsage struct {
+	// Number of bytes read from the files and the zip entries
+	BytesRead int64
+	// Number of entries of the zip files opened
+	ZipEntries int
+	// Deepest nested jar read
+	NestedJarDepth int
+}
+
+// budgets of the running fingerprint, their usage and the budgets that it has exceeded
+var budgetState = struct {
+	sync.Mutex
+	budgets  Budgets
+	deadline time.Time
+	usage    BudgetUsage
+	exceeded []*BudgetExceededError
+}{budgets: DefaultBudgets}
+
+// StartBudgets sets the budgets of the fingerprint that starts running (its timeout starts now)
+// and forgets the usage and the budgets exceeded by the previous fingerprint
+func StartBudgets(budgets Budgets) {
+	budgetState.Lock()
+	defer budgetState.Unlock()
+	budgetState.budgets = budgets.WithDefaults()
+	budgetState.deadline = time.Now().Add(budgetState.budgets.Timeout)
+	budgetState.usage = BudgetUsage{}
+	budgetState.exceeded = nil
+}
+
+// CurrentBudgetUsage returns the usage of the budgets by the running fingerprint
+func CurrentBudgetUsage() BudgetUsage {
+	budgetState.Lock()
+	defer budgetState.Unlock()
+	return budgetState.usage
+}
+
+// addBudgetUsage adds the usage to the usage of the running fingerprint
+func addBudgetUsage(bytesRead int64, zipEntries int, nestedJarDepth int) {
+	budgetState.Lock()
+	defer budgetState.Unlock()
+	budgetState.usage.BytesRead += bytesRead
+	budgetState.usage.ZipEntries += zipEntries
+	budgetState.usage.NestedJarDepth = max(budgetState.usage.NestedJarDepth, nestedJarDepth)
+}
+
+// CurrentBudgets returns the budgets of the running fingerprint
+func CurrentBudgets() Budgets {
+	budgetState.Lock()
+	defer budgetState.Unlock()
+	return budgetState.budgets
+}
+
+// ExceededBudgets returns the budgets exceeded by the running fingerprint (once per budget and path)
+func ExceededBudgets() []*BudgetExceededError {
+	budgetState.Lock()
+	defer budgetState.Unlock()
+	return append([]*BudgetExceededError{}, budgetState.exceeded...)
+}
+
+// BudgetExceeded records that a budget of the running fingerprint is exceeded and returns the corresponding error
+func BudgetExceeded(budget string, limit string, path string) error {
+	err := &BudgetExceededError{Budget: budget, Limit: limit, Path: path}
+	budgetState.Lock()
+	defer budgetState.Unlock()
+	for _, exceeded := range budgetState.exceeded {
+		if *exceeded == *err {
+			return err
+		}
+	}
+	budgetState.exceeded = append(budgetState.exceeded, err)
+	return err
+}
+
+// checkDeadline returns an error if the timeout of the running fingerprint is exceeded
+// so that the fingerprint stops at its next file access
+func checkDeadline() error {
+	budgetState.Lock()
+	deadline, timeout := budgetState.deadline, budgetState.budgets.Timeout
+	budgetState.Unlock()
+	if !deadline.IsZero() && time.Now().After(deadline) {
+		return BudgetExceeded(BudgetTimeout, timeout.String(), "")
+	}
+	return nil
+}
+
+// deadlineContext returns a context that is cancelled at the deadline of the running fingerprint
+func deadlineContext() (context.Context, context.CancelFunc) {
+	budgetState.Lock()
+	deadline := budgetState.deadline
+	budgetState.Unlock()
+	if deadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// LimitReader returns a reader of the file at path that fails with a BudgetExceededError
+// when more than the max-file-bytes budget is read
+func LimitReader(path string, r io.Reader) io.Reader {
+	return &limitedReader{r: r, path: path, remaining: CurrentBudgets().MaxFileBytes}
+}
+
+type limitedReader struct {
+	r         io.Reader
+	path      string
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		// the file can end exactly at the budget
+		if n, err := l.r.Read(make([]byte, 1)); n == 0 && err != nil {
+			return 0, err
+		}
+		return 0, BudgetExceeded(BudgetMaxFileBytes, strconv.FormatInt(CurrentBudgets().MaxFileBytes, 10), l.path)
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	addBudgetUsage(int64(n), 0, 0)
+	return n, err
+}
+
+// checkZipEntries returns an error if the zip file at path has more entries than the max-zip-entries budget
+func checkZipEntries(path string, r *zip.Reader) error {
+	addBudgetUsage(0, len(r.File), 0)
+	if maxEntries := CurrentBudgets().MaxZipEntries; len(r.File) > maxEntries {
+		return BudgetExceeded(BudgetMaxZipEntries, strconv.Itoa(maxEntries), path)
+	}
+	return nil
+}
+
+// OpenZipEntry opens an entry of a zip file after checking its decompression ratio and its size.
+// The returned reader fails if more than the max-file-bytes budget is read.
+func OpenZipEntry(file *zip.File) (io.ReadCloser, error) {
+	budgets := CurrentBudgets()
+	if file.CompressedSize64 > 0 && file.UncompressedSize64/file.CompressedSize64 > uint64(budgets.MaxDecompressionRatio) {
+		return nil, BudgetExceeded(BudgetMaxDecompressionRatio, strconv.FormatInt(budgets.MaxDecompressionRatio, 10), file.Name)
+	}
+	if file.UncompressedSize64 > uint64(budgets.MaxFileBytes) {
+		return nil, BudgetExceeded(BudgetMaxFileBytes, strconv.FormatInt(budgets.MaxFileBytes, 10), file.Name)
+	}
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{LimitReader(file.Name, r), r}, nil
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withBudgets(t *testing.T, budgets Budgets) {
+	StartBudgets(budgets)
+	t.Cleanup(func() { StartBudgets(Budgets{Timeout: time.Hour}) })
+}
+
+func assertBudgetExceeded(t *testing.T, err error, budget string) {
+	var exceeded *BudgetExceededError
+	if assert.True(t, errors.As(err, &exceeded), "unexpected error %v", err) {
+		assert.Equal(t, budget, exceeded.Budget)
+	}
+	assert.Contains(t, ExceededBudgets(), exceeded)
+}
+
+func TestBudgetsWithDefaults(t *testing.T) {
+	assert.Equal(t, DefaultBudgets, Budgets{}.WithDefaults())
+	budgets := Budgets{Timeout: time.Second, MaxZipEntries: 10}.WithDefaults()
+	assert.Equal(t, time.Second, budgets.Timeout)
+	assert.Equal(t, 10, budgets.MaxZipEntries)
+	assert.Equal(t, DefaultBudgets.MaxFileBytes, budgets.MaxFileBytes)
+}
+
+func TestMaxFileBytes(t *testing.T) {
+	withBudgets(t, Budgets{MaxFileBytes: 4})
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "small"), []byte("1234"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "large"), []byte("12345"), 0644))
+
+	content, err := ReadFile(dir, "/small")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", string(content))
+	assert.Empty(t, ExceededBudgets())
+
+	_, err = ReadFile(dir, "/large")
+	assertBudgetExceeded(t, err, BudgetMaxFileBytes)
+	assert.EqualError(t, err, "budget exceeded: max-file-bytes of /large (limit 4)")
+
+	_, err = io.ReadAll(LimitReader("stream", strings.NewReader("123456")))
+	assertBudgetExceeded(t, err, BudgetMaxFileBytes)
+}
+
+func TestMaxZipEntries(t *testing.T) {
+	withBudgets(t, Budgets{MaxZipEntries: 2})
+	dir := t.TempDir()
+	writeJar(t, filepath.Join(dir, "app.jar"), map[string][]byte{"a": nil, "b": nil, "c": nil})
+
+	_, err := OpenJar(dir, "/app.jar")
+	assertBudgetExceeded(t, err, BudgetMaxZipEntries)
+	assert.False(t, JarFileContainsClass(dir, "/app.jar", "a"))
+}
+
+func TestMaxDecompressionRatio(t *testing.T) {
+	withBudgets(t, Budgets{MaxDecompressionRatio: 10})
+	dir := t.TempDir()
+	writeJar(t, filepath.Join(dir, "bomb.jar"), map[string][]byte{
+		"META-INF/MANIFEST.MF": bytes.Repeat([]byte("A"), 1<<20),
+	})
+
+	_, err := GetJarManifest(dir, "/bomb.jar")
+	assertBudgetExceeded(t, err, BudgetMaxDecompressionRatio)
+}
+
+func TestMaxNestedJarDepth(t *testing.T) {
+	withBudgets(t, Budgets{})
+	dir := t.TempDir()
+	innerJar := createJar(t, map[string][]byte{"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\n")})
+	nestedJar := createJar(t, map[string][]byte{"lib/inner.jar": innerJar})
+	writeJar(t, filepath.Join(dir, "app.jar"), map[string][]byte{"BOOT-INF/lib/nested.jar": nestedJar})
+
+	r, err := OpenNestedJar(dir, "/app.jar", "BOOT-INF/lib/nested.jar")
+	assert.NoError(t, err)
+	// a jar packaged in a nested jar is deeper than the default budget
+	_, err = ReadNestedJar(r.File[0], 2)
+	assertBudgetExceeded(t, err, BudgetMaxNestedJarDepth)
+}
+
+func TestTimeout(t *testing.T) {
+	withBudgets(t, Budgets{Timeout: time.Millisecond})
+	time.Sleep(5 * time.Millisecond)
+
+	_, err := Open(t.TempDir(), "/file")
+	assertBudgetExceeded(t, err, BudgetTimeout)
+	assert.EqualError(t, err, "budget exceeded: timeout (limit 1ms)")
+}
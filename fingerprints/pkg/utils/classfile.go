@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	classFileMagic = 0xCAFEBABE
+)
+
+// ClassFileName returns the name of the file that contains the bytecode of the given class
+// (for example `com/example/Main.class` for `com.example.Main`)
+func ClassFileName(className string) string {
+	return strings.ReplaceAll(className, ".", "/") + ".class"
+}
+
+// ReadClassMajorVersion returns the major version of a class file
+func ReadClassMajorVersion(r io.Reader) (uint16, error) {
+	// a class file starts with its magic number (u4), its minor version (u2) and its major version (u2)
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, fmt.Errorf("failed to read class file header: %w", err)
+	}
+	if binary.BigEndian.Uint32(header[0:4]) != classFileMagic {
+		return 0, fmt.Errorf("invalid class file magic number")
+	}
+	return binary.BigEndian.Uint16(header[6:8]), nil
+}
+
+// GetJarClassMajorVersion returns the major version of the class file entry in the given jar
+func GetJarClassMajorVersion(root string, jarPath string, classFileEntry string) (uint16, error) {
+	r, err := OpenJar(root, jarPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open JAR file: %w", err)
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		if file.Name == classFileEntry {
+			classFile, err := OpenZipEntry(file)
+			if err != nil {
+				return 0, fmt.Errorf("failed to open class file %s: %w", classFileEntry, err)
+			}
+			defer classFile.Close()
+			return ReadClassMajorVersion(classFile)
+		}
+	}
+	return 0, fmt.Errorf("class file %s not found in jar %s", classFileEntry, jarPath)
+}
+
+// JavaReleaseFromClassMajorVersion returns the Java release corresponding to a class file major version
+// (for example `8` for 52 or `21` for 65)
+func JavaReleaseFromClassMajorVersion(majorVersion uint16) string {
+	switch {
+	case majorVersion < 45:
+		return ""
+	case majorVersion < 49:
+		// 45 is 1.1 (and 1.0.2), 46 is 1.2, 47 is 1.3, 48 is 1.4
+		return "1." + strconv.Itoa(int(majorVersion)-44)
+	default:
+		// 49 is Java 5
+		return strconv.Itoa(int(majorVersion) - 44)
+	}
+}
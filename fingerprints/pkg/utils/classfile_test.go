@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassFileName(t *testing.T) {
+	assert.Equal(t, "com/example/Main.class", ClassFileName("com.example.Main"))
+}
+
+func TestReadClassMajorVersion(t *testing.T) {
+	version, err := ReadClassMajorVersion(bytes.NewReader(classFileHeader(61)))
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(61), version)
+
+	_, err = ReadClassMajorVersion(bytes.NewReader([]byte{0xCA, 0xFE}))
+	assert.Error(t, err)
+
+	_, err = ReadClassMajorVersion(bytes.NewReader([]byte{0, 0, 0, 0, 0, 0, 0, 61}))
+	assert.Error(t, err)
+}
+
+func TestGetJarClassMajorVersion(t *testing.T) {
+	jarPath := filepath.Join(t.TempDir(), "app.jar")
+	writeJar(t, jarPath, map[string][]byte{
+		"BOOT-INF/classes/com/example/Main.class": classFileHeader(52),
+	})
+
+	version, err := GetJarClassMajorVersion("", jarPath, "BOOT-INF/classes/com/example/Main.class")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(52), version)
+
+	_, err = GetJarClassMajorVersion("", jarPath, "com/example/Main.class")
+	assert.Error(t, err)
+}
+
+func TestJavaReleaseFromClassMajorVersion(t *testing.T) {
+	assert.Equal(t, "", JavaReleaseFromClassMajorVersion(44))
+	assert.Equal(t, "1.1", JavaReleaseFromClassMajorVersion(45))
+	assert.Equal(t, "1.4", JavaReleaseFromClassMajorVersion(48))
+	assert.Equal(t, "5", JavaReleaseFromClassMajorVersion(49))
+	assert.Equal(t, "8", JavaReleaseFromClassMajorVersion(52))
+	assert.Equal(t, "17", JavaReleaseFromClassMajorVersion(61))
+	assert.Equal(t, "21", JavaReleaseFromClassMajorVersion(65))
+}
+
+func classFileHeader(majorVersion byte) []byte {
+	return []byte{0xCA, 0xFE, 0xBA, 0xBE, 0, 0, 0, majorVersion}
+}
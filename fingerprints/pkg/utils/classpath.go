@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GetClasspathJars returns the jars that compose the effective classpath of a Java application.
+//
+// The effective classpath is composed of:
+// - the executable jar (if any)
+// - the jars listed in the `Class-Path` entry of the executable jar manifest (relative to the executable jar)
+// - the jars listed in the classpath (`-cp` or `-classpath` arguments). Wildcard entries (`lib/*`) are expanded.
+func GetClasspathJars(root string, executableJar string, classpath string) []string {
+	jars := []string{}
+	if executableJar != "" {
+		jars = append(jars, executableJar)
+		if manifest, err := GetJarManifest(root, executableJar); err == nil {
+			for _, otherJar := range strings.Fields(manifest.Main.Get("Class-Path")) {
+				if !filepath.IsAbs(otherJar) {
+					otherJar = filepath.Join(filepath.Dir(executableJar), otherJar)
+				}
+				jars = append(jars, otherJar)
+			}
+		}
+	}
+	for _, entry := range strings.Split(classpath, string(os.PathListSeparator)) {
+		switch {
+		case entry == "":
+			continue
+		case strings.HasSuffix(entry, "*"):
+			jars = append(jars, Glob(root, filepath.Join(filepath.Dir(entry), "*.jar"))...)
+		case strings.HasSuffix(entry, ".jar"):
+			jars = append(jars, entry)
+		}
+	}
+	return jars
+}
+
+// ListNestedJars returns the names of the jar entries that are packaged inside the given jar
+// (for example `BOOT-INF/lib/*.jar` for Spring Boot applications or `WEB-INF/lib/*.jar` for web archives)
+func ListNestedJars(root string, jarPath string) []string {
+	r, err := OpenJar(root, jarPath)
+	if err != nil {
+		return nil
+	}
+	defer r.Close()
+
+	nestedJars := []string{}
+	for _, file := range r.File {
+		if strings.HasSuffix(file.Name, ".jar") {
+			nestedJars = append(nestedJars, file.Name)
+		}
+	}
+	return nestedJars
+}
+
+// OpenNestedJar reads in memory the jar entry packaged inside the given jar
+func OpenNestedJar(root string, jarPath string, entryName string) (*zip.Reader, error) {
+	r, err := OpenJar(root, jarPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open JAR file: %w", err)
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		if file.Name == entryName {
+			return ReadNestedJar(file, 1)
+		}
+	}
+	return nil, fmt.Errorf("nested jar %s not found in jar %s", entryName, jarPath)
+}
+
+// ReadNestedJar reads in memory a jar entry of an opened jar.
+// The depth is the depth of the nested jar (1 for a jar packaged in a jar of the filesystem).
+func ReadNestedJar(file *zip.File, depth int) (*zip.Reader, error) {
+	if maxDepth := CurrentBudgets().MaxNestedJarDepth; depth > maxDepth {
+		return nil, BudgetExceeded(BudgetMaxNestedJarDepth, strconv.Itoa(maxDepth), file.Name)
+	}
+	addBudgetUsage(0, 0, depth)
+	nestedJar, err := OpenZipEntry(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open nested jar %s: %w", file.Name, err)
+	}
+	defer nestedJar.Close()
+
+	content, err := io.ReadAll(nestedJar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read nested jar %s: %w", file.Name, err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+	if err := checkZipEntries(file.Name, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MatchJarVersion checks whether the name of the jar corresponds to the given artifact and returns its version.
+//
+// The jar name can be prefixed by a group ID (for example `org.jetbrains.kotlin.kotlin-stdlib-1.9.22.jar` in Quarkus applications).
+// The returned version is empty if the jar name does not contain it.
+func MatchJarVersion(jarPath string, artifact string) (string, bool) {
+	matches := jarNameRegex(artifact).FindStringSubmatch(path.Base(jarPath))
+	if matches == nil {
+		return "", false
+	}
+	return matches[1], true
+}
+
+// jarNameRegexes are the regexes of the jar names of the artifacts, compiled once per artifact
+var jarNameRegexes sync.Map
+
+// jarNameRegex returns the regex matching the jar names of the artifact, with the version as first group
+func jarNameRegex(artifact string) *regexp.Regexp {
+	if re, found := jarNameRegexes.Load(artifact); found {
+		return re.(*regexp.Regexp)
+	}
+	re, _ := jarNameRegexes.LoadOrStore(artifact, regexp.MustCompile(`^(?:.*\.)?`+regexp.QuoteMeta(artifact)+`(?:-(\d[^/]*))?\.jar$`))
+	return re.(*regexp.Regexp)
+}
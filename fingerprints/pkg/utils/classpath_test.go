@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchJarVersion(t *testing.T) {
+	version, found := MatchJarVersion("/deployments/lib/kotlin-stdlib-1.9.22.jar", "kotlin-stdlib")
+	assert.True(t, found)
+	assert.Equal(t, "1.9.22", version)
+
+	version, found = MatchJarVersion("lib/main/org.jetbrains.kotlin.kotlin-stdlib-1.9.22.jar", "kotlin-stdlib")
+	assert.True(t, found)
+	assert.Equal(t, "1.9.22", version)
+
+	version, found = MatchJarVersion("BOOT-INF/lib/clojure.jar", "clojure")
+	assert.True(t, found)
+	assert.Equal(t, "", version)
+
+	_, found = MatchJarVersion("kotlin-stdlib-jdk8-1.9.22.jar", "kotlin-stdlib")
+	assert.False(t, found)
+
+	_, found = MatchJarVersion("mygroovy-4.0.15.jar", "groovy")
+	assert.False(t, found)
+
+	// the regex of an artifact is compiled once
+	assert.Same(t, jarNameRegex("kotlin-stdlib"), jarNameRegex("kotlin-stdlib"))
+}
+
+func TestGetClasspathJars(t *testing.T) {
+	dir := t.TempDir()
+	libDir := filepath.Join(dir, "lib")
+	assert.NoError(t, os.Mkdir(libDir, 0755))
+
+	appJar := filepath.Join(dir, "app.jar")
+	writeJar(t, appJar, map[string][]byte{
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\r\nClass-Path: lib/a.jar lib/b.jar\r\n"),
+	})
+	writeJar(t, filepath.Join(libDir, "c.jar"), map[string][]byte{})
+	writeJar(t, filepath.Join(libDir, "d.jar"), map[string][]byte{})
+
+	jars := GetClasspathJars("", appJar, "")
+	assert.Equal(t, []string{appJar, filepath.Join(libDir, "a.jar"), filepath.Join(libDir, "b.jar")}, jars)
+
+	jars = GetClasspathJars("", "", filepath.Join(dir, "classes")+":"+filepath.Join(libDir, "*"))
+	assert.Equal(t, []string{filepath.Join(libDir, "c.jar"), filepath.Join(libDir, "d.jar")}, jars)
+}
+
+func TestNestedJars(t *testing.T) {
+	dir := t.TempDir()
+
+	nestedJar := createJar(t, map[string][]byte{
+		"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0\nImplementation-Version: 1.11.1\n"),
+	})
+	appJar := filepath.Join(dir, "app.jar")
+	writeJar(t, appJar, map[string][]byte{
+		"META-INF/MANIFEST.MF":     []byte("Manifest-Version: 1.0\n"),
+		"BOOT-INF/lib/clojure.jar": nestedJar,
+	})
+
+	assert.Equal(t, []string{"BOOT-INF/lib/clojure.jar"}, ListNestedJars("", appJar))
+
+	r, err := OpenNestedJar("", appJar, "BOOT-INF/lib/clojure.jar")
+	assert.NoError(t, err)
+	manifest, err := ReadJarManifest(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.11.1", manifest.Main.Get("Implementation-Version"))
+
+	_, err = OpenNestedJar("", appJar, "BOOT-INF/lib/missing.jar")
+	assert.Error(t, err)
+}
+
+func createJar(t *testing.T, entries map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	w := zip.NewWriter(&buffer)
+	for name, content := range entries {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = f.Write(content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return buffer.Bytes()
+}
+
+func writeJar(t *testing.T, jarPath string, entries map[string][]byte) {
+	assert.NoError(t, os.WriteFile(jarPath, createJar(t, entries), 0644))
+}
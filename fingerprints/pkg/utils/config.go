@@ -1,7 +1,14 @@
 package utils
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
@@ -10,14 +17,60 @@ type Config struct {
 	Fingerprints Fingerprints `toml:"fingerprints"`
 }
 
+// Version detection modes of the runtimes of the version-executables
+const (
+	// the version is the output of the `--version` of the executable (default)
+	VersionDetectionExec = "exec"
+	// the version is read from the files of the runtime, without running any executable of the container
+	VersionDetectionStatic = "static"
+)
+
 type Fingerprints struct {
+	// Version detection mode (VersionDetectionExec or VersionDetectionStatic).
+	// The static mode disables all the fingerprints that run executables of the container.
+	VersionDetection   string                   `toml:"version-detection"`
 	VersionExecutables []VersionExecutable      `toml:"version-executables"`
 	Java               []JavaRuntimeExecutables `toml:"java"`
+	JvmLanguages       []JvmLanguage            `toml:"jvm-languages"`
+	Agents             []Agent                  `toml:"agents"`
+	OsPackages         OsPackages               `toml:"os-packages"`
+	FileRules          []FileRule               `toml:"file-rules"`
+	Budgets            Budgets                  `toml:"budgets"`
 }
 
+// Output streams of the version-executables that print their version
+const (
+	CaptureStdout = "stdout"
+	CaptureStderr = "stderr"
+	// the standard output and the standard error, interleaved like on a terminal
+	CaptureBoth = "both"
+)
+
 type VersionExecutable struct {
 	ProcessNames    []string `toml:"process-names"`
 	RuntimeKindName string   `toml:"runtime-kind-name"`
+	// Arguments of the executable that print its version (`--version` if they are not set)
+	Arguments []string `toml:"arguments,omitempty"`
+	// Output stream printing the version (CaptureStdout, CaptureStderr or CaptureBoth; CaptureStdout if it is not set)
+	Capture string `toml:"capture,omitempty"`
+	// Regular expression extracting the version from the output with its `version` named group.
+	// The version is the trimmed output if it is not set.
+	VersionRegex string `toml:"version-regex,omitempty"`
+	// Regular expression extracting the implementer of the runtime from the output with its `implementer` named group
+	ImplementerRegex string `toml:"implementer-regex,omitempty"`
+	// VersionRegex and ImplementerRegex compiled when the configuration is read (nil if they are not set)
+	CompiledVersionRegex     *regexp.Regexp `toml:"-"`
+	CompiledImplementerRegex *regexp.Regexp `toml:"-"`
+	// Duration after which the executable is killed (for example "5s"). The timeout budget of the fingerprint still applies.
+	Timeout time.Duration `toml:"timeout,omitempty"`
+}
+
+// VersionArguments returns the arguments of the executable that print its version
+func (ve VersionExecutable) VersionArguments() []string {
+	if len(ve.Arguments) == 0 {
+		return []string{"--version"}
+	}
+	return ve.Arguments
 }
 
 type JavaRuntimeExecutables struct {
@@ -28,16 +81,206 @@ type JavaRuntimeExecutables struct {
 	JarVersionManifestEntry     string `toml:"jar-version-manifest-entry"`
 }
 
-func GetConfig(filepath string) (Config, error) {
-	content, err := os.ReadFile(filepath)
-	if err != nil {
-		return Config{}, err
+type JvmLanguage struct {
+	RuntimeName string `toml:"runtime-name"`
+	// Names of the artifacts providing the language standard library.
+	// They are checked in order and the first one found on the classpath provides the version of the language.
+	JarNames []string `toml:"jar-names"`
+}
+
+type Agent struct {
+	AgentName string `toml:"agent-name"`
+	// Prefix of the Premain-Class manifest entry of the Java agent jar
+	JavaPremainClassPrefix string `toml:"java-premain-class-prefix,omitempty"`
+	// Node.js modules preloaded with --require
+	NodeModules []string `toml:"node-modules,omitempty"`
+	// Path of the directory containing the sitecustomize module that bootstraps the Python agent
+	PythonBootstrapPath string `toml:"python-bootstrap-path,omitempty"`
+	// Name of the Python distribution of the agent
+	PythonDistribution string `toml:"python-distribution,omitempty"`
+	// Name of the executable that wraps the Python application
+	PythonWrapper string `toml:"python-wrapper,omitempty"`
+}
+
+// How the file rules read the version of the runtime from their file
+const (
+	// the runtime is detected when the file exists (its version is not read)
+	FileRuleExists = "exists"
+	// the version is the value of the key of a key=value file (see the keyvalue module)
+	FileRuleProperties = "properties"
+	// the version is the value at the dotted path of the key in a JSON document (for example "engines.node")
+	FileRuleJSON = "json"
+	// the version is the `version` named group of the regex matching the content of the file
+	FileRuleRegex = "regex"
+)
+
+// Directories that the relative paths of the file rules are resolved against
+const (
+	// the root directory of the container (default)
+	FileRuleRelativeToRoot = "root"
+	// the current working directory of the process
+	FileRuleRelativeToCwd = "cwd"
+	// the installation directory of the executable of the process (the parent of its bin directory)
+	FileRuleRelativeToInstallDir = "install-dir"
+)
+
+// FileRule detects a runtime from a file of the container without a dedicated fingerprint
+type FileRule struct {
+	RuntimeName string `toml:"runtime-name"`
+	// Names of the processes that the rule applies to (all the processes if it is not set)
+	ProcessNames []string `toml:"process-names,omitempty"`
+	// Directory that the relative paths are resolved against (FileRuleRelativeToRoot if it is not set)
+	RelativeTo string `toml:"relative-to,omitempty"`
+	// Paths (with the syntax of path.Match) of the file. The first matching file that gives a version is used.
+	Paths []string `toml:"paths"`
+	// Entry of the zip file (jar) at the path that is read instead of the file
+	Entry string `toml:"entry,omitempty"`
+	// How the version is read (FileRuleExists, FileRuleProperties, FileRuleJSON or FileRuleRegex)
+	Extract string `toml:"extract"`
+	// Key of the properties file or dotted path of the JSON document
+	Key string `toml:"key,omitempty"`
+	// Regular expression extracting the version from the content of the file with its `version` named group
+	Regex string `toml:"regex,omitempty"`
+	// Regex compiled when the configuration is read (nil if it is not set)
+	CompiledRegex *regexp.Regexp `toml:"-"`
+}
+
+type OsPackages struct {
+	// Patterns (with the syntax of path.Match) of the names of the packages to report
+	AllowList []string `toml:"allow-list"`
+}
+
+// Name of the directory of the drop-in configuration files, alongside the base configuration file
+const ConfigDropInDir = "config.d"
+
+// Keys identifying the entries of the arrays of tables of the configuration.
+// An entry of a drop-in file replaces the entry of the base configuration that has the same identity.
+var configEntryIdentities = map[string]string{
+	"version-executables": "runtime-kind-name",
+	"java":                "runtime-name",
+	"jvm-languages":       "runtime-name",
+	"agents":              "agent-name",
+	"file-rules":          "runtime-name",
+}
+
+// ConfigFiles returns the files of the configuration: the base configuration file
+// followed by the drop-in files (*.toml) of the config.d directory alongside it, sorted by name
+func ConfigFiles(path string) []string {
+	dropIns, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ConfigDropInDir, "*.toml"))
+	sort.Strings(dropIns)
+	return append([]string{path}, dropIns...)
+}
+
+// GetConfig reads the configuration file and merges the drop-in files of its config.d directory into it (see ConfigFiles).
+//
+// The drop-in files are merged in order:
+// the tables are merged key by key, the values of a drop-in file replace the values of the configuration,
+// and the entries of the arrays of tables (such as the agents) replace the entries with the same identity
+// (see configEntryIdentities) or are appended.
+// The regexes of the merged configuration are compiled once: an invalid regex fails the read of the configuration.
+func GetConfig(path string) (Config, error) {
+	merged := map[string]any{}
+	for _, file := range ConfigFiles(path) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return Config{}, err
+		}
+		document := map[string]any{}
+		if _, err := toml.Decode(string(content), &document); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", file, err)
+		}
+		mergeConfig(merged, document)
 	}
 
+	// the merged document is decoded like a single configuration file
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(merged); err != nil {
+		return Config{}, err
+	}
 	var config Config
-	_, err = toml.Decode(string(content), &config)
+	_, err := toml.Decode(buf.String(), &config)
 	if err != nil {
 		return Config{}, err
 	}
+	if err := compileConfigRegexes(&config); err != nil {
+		return Config{}, err
+	}
 	return config, nil
 }
+
+// compileConfigRegexes compiles the regexes of the version-executables and of the file rules
+func compileConfigRegexes(config *Config) error {
+	var err error
+	for i := range config.Fingerprints.VersionExecutables {
+		ve := &config.Fingerprints.VersionExecutables[i]
+		if ve.CompiledVersionRegex, err = compileRegex(ve.VersionRegex, "version"); err != nil {
+			return fmt.Errorf("version-executable %s: invalid version-regex %q: %w", ve.RuntimeKindName, ve.VersionRegex, err)
+		}
+		if ve.CompiledImplementerRegex, err = compileRegex(ve.ImplementerRegex, "implementer"); err != nil {
+			return fmt.Errorf("version-executable %s: invalid implementer-regex %q: %w", ve.RuntimeKindName, ve.ImplementerRegex, err)
+		}
+	}
+	for i := range config.Fingerprints.FileRules {
+		rule := &config.Fingerprints.FileRules[i]
+		if rule.CompiledRegex, err = compileRegex(rule.Regex, "version"); err != nil {
+			return fmt.Errorf("file rule %s: invalid regex %q: %w", rule.RuntimeName, rule.Regex, err)
+		}
+	}
+	return nil
+}
+
+// compileRegex compiles the pattern of the configuration, which must have the named group.
+// It returns nil if the pattern is empty.
+func compileRegex(pattern string, group string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re.SubexpIndex(group) < 0 {
+		return nil, fmt.Errorf("missing the %q named group", group)
+	}
+	return re, nil
+}
+
+// mergeConfig merges the TOML document of a drop-in file into the base document
+func mergeConfig(base map[string]any, dropIn map[string]any) {
+	for key, value := range dropIn {
+		switch value := value.(type) {
+		case map[string]any:
+			if baseTable, ok := base[key].(map[string]any); ok {
+				mergeConfig(baseTable, value)
+				continue
+			}
+		case []map[string]any:
+			identity, hasIdentity := configEntryIdentities[key]
+			if baseEntries, ok := base[key].([]map[string]any); ok && hasIdentity {
+				base[key] = mergeConfigEntries(baseEntries, value, identity)
+				continue
+			}
+		}
+		base[key] = value
+	}
+}
+
+// mergeConfigEntries replaces the base entries by the drop-in entries with the same identity and appends the other drop-in entries.
+// Only string identities match: the entries with an invalid identity (such as an array) are appended
+// and reported when the merged configuration is decoded.
+func mergeConfigEntries(base []map[string]any, dropIn []map[string]any, identity string) []map[string]any {
+	merged := slices.Clone(base)
+	for _, entry := range dropIn {
+		idx := slices.IndexFunc(merged, func(baseEntry map[string]any) bool {
+			id, isString := baseEntry[identity].(string)
+			dropInID, dropInIsString := entry[identity].(string)
+			return isString && dropInIsString && id == dropInID
+		})
+		if idx >= 0 {
+			merged[idx] = entry
+		} else {
+			merged = append(merged, entry)
+		}
+	}
+	return merged
+}
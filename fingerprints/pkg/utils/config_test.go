@@ -1,7 +1,11 @@
 package utils
 
 import (
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,5 +17,154 @@ func TestReadConfig(t *testing.T) {
 	}
 
 	assert.Equal(t, 2, len(config.Fingerprints.VersionExecutables))
+	assert.Equal(t, []string{"--version"}, config.Fingerprints.VersionExecutables[0].VersionArguments())
+	assert.Equal(t, CaptureBoth, config.Fingerprints.VersionExecutables[1].Capture)
 	assert.Equal(t, 3, len(config.Fingerprints.Java))
+	assert.Equal(t, 4, len(config.Fingerprints.JvmLanguages))
+	assert.Equal(t, 6, len(config.Fingerprints.Agents))
+	assert.Equal(t, 4, len(config.Fingerprints.FileRules))
+	assert.Contains(t, config.Fingerprints.OsPackages.AllowList, "openssl*")
+	assert.Equal(t, DefaultBudgets, config.Fingerprints.Budgets)
+}
+
+func TestReadVersionExecutableConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+[[fingerprints.version-executables]]
+process-names = ["nginx"]
+runtime-kind-name = "nginx"
+arguments = ["-v"]
+capture = "stderr"
+version-regex = 'nginx/(?P<version>[0-9.]+)'
+timeout = "5s"
+`), 0644))
+
+	config, err := GetConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []VersionExecutable{{
+		ProcessNames:    []string{"nginx"},
+		RuntimeKindName: "nginx",
+		Arguments:       []string{"-v"},
+		Capture:         CaptureStderr,
+		VersionRegex:    `nginx/(?P<version>[0-9.]+)`,
+		// the regexes are compiled when the configuration is read
+		CompiledVersionRegex: regexp.MustCompile(`nginx/(?P<version>[0-9.]+)`),
+		Timeout:              5 * time.Second,
+	}}, config.Fingerprints.VersionExecutables)
+	assert.Equal(t, []string{"-v"}, config.Fingerprints.VersionExecutables[0].VersionArguments())
+}
+
+func TestReadConfigInvalidRegex(t *testing.T) {
+	for _, test := range []struct {
+		config string
+		err    string
+	}{
+		{
+			config: "[[fingerprints.version-executables]]\nprocess-names = [\"node\"]\nruntime-kind-name = \"Node.js\"\nversion-regex = '^v(\\S+)'\n",
+			err:    `version-executable Node.js: invalid version-regex "^v(\\S+)": missing the "version" named group`,
+		},
+		{
+			config: "[[fingerprints.version-executables]]\nprocess-names = [\"java\"]\nruntime-kind-name = \"Java\"\nimplementer-regex = '(?P<implementer>'\n",
+			err:    "version-executable Java: invalid implementer-regex \"(?P<implementer>\": error parsing regexp: missing closing ): `(?P<implementer>`",
+		},
+		{
+			config: "[[fingerprints.file-rules]]\nruntime-name = \"Supervisor\"\npaths = [\"/etc/supervisord.conf\"]\nextract = \"regex\"\nregex = '[0-9]+'\n",
+			err:    `file rule Supervisor: invalid regex "[0-9]+": missing the "version" named group`,
+		},
+	} {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		assert.NoError(t, os.WriteFile(path, []byte(test.config), 0644))
+		_, err := GetConfig(path)
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func TestReadConfigDropIns(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.toml"), []byte(`
+[fingerprints]
+version-detection = "exec"
+
+[[fingerprints.version-executables]]
+process-names = ["node"]
+runtime-kind-name = "Node.js"
+
+[[fingerprints.agents]]
+agent-name = "OpenTelemetry"
+node-modules = ["@opentelemetry/auto-instrumentations-node"]
+
+[[fingerprints.agents]]
+agent-name = "Datadog"
+node-modules = ["dd-trace"]
+
+[fingerprints.os-packages]
+allow-list = ["openssl*"]
+
+[fingerprints.budgets]
+timeout = "30s"
+max-zip-entries = 1000
+`), 0644))
+	dropInDir := filepath.Join(dir, ConfigDropInDir)
+	assert.NoError(t, os.MkdirAll(dropInDir, 0755))
+	// the drop-in files are merged in the order of their names
+	assert.NoError(t, os.WriteFile(filepath.Join(dropInDir, "20-site.toml"), []byte(`
+[[fingerprints.agents]]
+agent-name = "Datadog"
+node-modules = ["dd-trace", "@datadog/pprof"]
+
+[fingerprints.budgets]
+timeout = "1m"
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dropInDir, "10-static.toml"), []byte(`
+[fingerprints]
+version-detection = "static"
+
+[[fingerprints.agents]]
+agent-name = "Acme"
+node-modules = ["acme-apm"]
+
+[fingerprints.os-packages]
+allow-list = ["glibc"]
+
+[fingerprints.budgets]
+timeout = "10s"
+`), 0644))
+	// only the *.toml files are drop-in files
+	assert.NoError(t, os.WriteFile(filepath.Join(dropInDir, "README"), []byte("not a configuration"), 0644))
+
+	assert.Equal(t, []string{
+		filepath.Join(dir, "config.toml"),
+		filepath.Join(dropInDir, "10-static.toml"),
+		filepath.Join(dropInDir, "20-site.toml"),
+	}, ConfigFiles(filepath.Join(dir, "config.toml")))
+
+	config, err := GetConfig(filepath.Join(dir, "config.toml"))
+	assert.NoError(t, err)
+	assert.Equal(t, VersionDetectionStatic, config.Fingerprints.VersionDetection)
+	assert.Equal(t, []VersionExecutable{{ProcessNames: []string{"node"}, RuntimeKindName: "Node.js"}}, config.Fingerprints.VersionExecutables)
+	// the agents with the same name are replaced in place, the others are appended
+	assert.Equal(t, []Agent{
+		{AgentName: "OpenTelemetry", NodeModules: []string{"@opentelemetry/auto-instrumentations-node"}},
+		{AgentName: "Datadog", NodeModules: []string{"dd-trace", "@datadog/pprof"}},
+		{AgentName: "Acme", NodeModules: []string{"acme-apm"}},
+	}, config.Fingerprints.Agents)
+	// the arrays of values are replaced
+	assert.Equal(t, []string{"glibc"}, config.Fingerprints.OsPackages.AllowList)
+	// the tables are merged key by key
+	assert.Equal(t, Budgets{Timeout: time.Minute, MaxZipEntries: 1000}, config.Fingerprints.Budgets)
+
+	_, err = GetConfig(filepath.Join(t.TempDir(), "config.toml"))
+	assert.Error(t, err)
+
+	// an identity that is not a string is not compared
+	assert.NoError(t, os.WriteFile(filepath.Join(dropInDir, "30-invalid.toml"), []byte(`
+[[fingerprints.agents]]
+agent-name = ["Datadog"]
+`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dropInDir, "40-invalid.toml"), []byte(`
+[[fingerprints.agents]]
+agent-name = ["Datadog"]
+`), 0644))
+	_, err = GetConfig(filepath.Join(dir, "config.toml"))
+	assert.Error(t, err)
 }
@@ -0,0 +1,304 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ConfigProblem is a problem of a configuration file reported by ValidateConfig
+type ConfigProblem struct {
+	File string
+	// Line of the problem in the file (0 if it is not known)
+	Line    int
+	Message string
+}
+
+func (p ConfigProblem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+}
+
+// ValidateConfig validates the configuration file and the drop-in files of its config.d directory (see ConfigFiles).
+//
+// Each file is validated on its own (an entry of a drop-in file replaces a whole entry of the configuration)
+// and the problems are reported with their line: TOML syntax errors, unknown keys, invalid values,
+// missing required fields, invalid regexes and duplicate runtime names.
+// It returns an error if a file can not be read.
+func ValidateConfig(path string) ([]ConfigProblem, error) {
+	problems := []ConfigProblem{}
+	for _, file := range ConfigFiles(path) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, validateConfigFile(file, string(content))...)
+	}
+	return problems, nil
+}
+
+// validateConfigFile returns the problems of the content of a configuration file
+func validateConfigFile(file string, content string) []ConfigProblem {
+	var config Config
+	md, err := toml.Decode(content, &config)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			// the line is reported by the problem
+			message := strings.TrimPrefix(parseErr.Error(), fmt.Sprintf("toml: line %d: ", parseErr.Position.Line))
+			message = strings.TrimPrefix(message, fmt.Sprintf("toml: line %d (last key %q): ", parseErr.Position.Line, parseErr.LastKey))
+			return []ConfigProblem{{File: file, Line: parseErr.Position.Line, Message: message}}
+		}
+		return []ConfigProblem{{File: file, Message: err.Error()}}
+	}
+
+	v := &configValidator{file: file, lines: locateConfigKeys(content)}
+	seen := map[string]int{}
+	for _, key := range md.Undecoded() {
+		name := key.String()
+		v.report(v.lines.occurrence(name, seen[name]), "unknown key %s", name)
+		seen[name]++
+	}
+
+	fingerprints := config.Fingerprints
+	switch fingerprints.VersionDetection {
+	case "", VersionDetectionExec, VersionDetectionStatic:
+	default:
+		v.report(v.lines.occurrence("fingerprints.version-detection", 0), "invalid version-detection %q (expected %q or %q)",
+			fingerprints.VersionDetection, VersionDetectionExec, VersionDetectionStatic)
+	}
+
+	for i, ve := range fingerprints.VersionExecutables {
+		entry := v.entry("version-executables", i)
+		entry.required("runtime-kind-name", ve.RuntimeKindName)
+		entry.required("process-names", ve.ProcessNames...)
+		switch ve.Capture {
+		case "", CaptureStdout, CaptureStderr, CaptureBoth:
+		default:
+			entry.report("capture", "invalid capture %q (expected %q, %q or %q)", ve.Capture, CaptureStdout, CaptureStderr, CaptureBoth)
+		}
+		entry.regex("version-regex", ve.VersionRegex, "version")
+		entry.regex("implementer-regex", ve.ImplementerRegex, "implementer")
+	}
+	v.duplicates("version-executables", "runtime-kind-name", len(fingerprints.VersionExecutables), func(i int) string {
+		return fingerprints.VersionExecutables[i].RuntimeKindName
+	})
+
+	for i, java := range fingerprints.Java {
+		entry := v.entry("java", i)
+		entry.required("runtime-name", java.RuntimeName)
+		entry.required("main-class", java.MainClass)
+		entry.required("jar-version-manifest-entry", java.JarVersionManifestEntry)
+	}
+	v.duplicates("java", "runtime-name", len(fingerprints.Java), func(i int) string { return fingerprints.Java[i].RuntimeName })
+
+	for i, language := range fingerprints.JvmLanguages {
+		entry := v.entry("jvm-languages", i)
+		entry.required("runtime-name", language.RuntimeName)
+		entry.required("jar-names", language.JarNames...)
+	}
+	v.duplicates("jvm-languages", "runtime-name", len(fingerprints.JvmLanguages), func(i int) string {
+		return fingerprints.JvmLanguages[i].RuntimeName
+	})
+
+	for i, agent := range fingerprints.Agents {
+		v.entry("agents", i).required("agent-name", agent.AgentName)
+	}
+	v.duplicates("agents", "agent-name", len(fingerprints.Agents), func(i int) string { return fingerprints.Agents[i].AgentName })
+
+	for i, rule := range fingerprints.FileRules {
+		entry := v.entry("file-rules", i)
+		entry.required("runtime-name", rule.RuntimeName)
+		entry.required("paths", rule.Paths...)
+		for _, pattern := range rule.Paths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				entry.report("paths", "invalid path pattern %q: %s", pattern, err)
+			}
+		}
+		switch rule.RelativeTo {
+		case "", FileRuleRelativeToRoot, FileRuleRelativeToCwd, FileRuleRelativeToInstallDir:
+		default:
+			entry.report("relative-to", "invalid relative-to %q (expected %q, %q or %q)",
+				rule.RelativeTo, FileRuleRelativeToRoot, FileRuleRelativeToCwd, FileRuleRelativeToInstallDir)
+		}
+		switch rule.Extract {
+		case "":
+			entry.required("extract", rule.Extract)
+		case FileRuleExists:
+		case FileRuleProperties, FileRuleJSON:
+			entry.required("key", rule.Key)
+		case FileRuleRegex:
+			entry.required("regex", rule.Regex)
+			entry.regex("regex", rule.Regex, "version")
+		default:
+			entry.report("extract", "invalid extract %q (expected %q, %q, %q or %q)",
+				rule.Extract, FileRuleExists, FileRuleProperties, FileRuleJSON, FileRuleRegex)
+		}
+	}
+	v.duplicates("file-rules", "runtime-name", len(fingerprints.FileRules), func(i int) string { return fingerprints.FileRules[i].RuntimeName })
+
+	for _, pattern := range fingerprints.OsPackages.AllowList {
+		if _, err := path.Match(pattern, ""); err != nil {
+			v.report(v.lines.occurrence("fingerprints.os-packages.allow-list", 0), "invalid package name pattern %q: %s", pattern, err)
+		}
+	}
+
+	sort.SliceStable(v.problems, func(i, j int) bool { return v.problems[i].Line < v.problems[j].Line })
+	return v.problems
+}
+
+// configValidator collects the problems of a configuration file
+type configValidator struct {
+	file     string
+	lines    configKeyLines
+	problems []ConfigProblem
+}
+
+func (v *configValidator) report(line int, format string, args ...any) {
+	v.problems = append(v.problems, ConfigProblem{File: v.file, Line: line, Message: fmt.Sprintf(format, args...)})
+}
+
+// entry returns the validator of the i-th entry of the [[fingerprints.<array>]] array of tables
+func (v *configValidator) entry(array string, i int) configEntryValidator {
+	return configEntryValidator{v: v, table: "fingerprints." + array, index: i}
+}
+
+// duplicates reports the entries of the array of tables whose identity is the identity of a previous entry
+func (v *configValidator) duplicates(array string, identity string, count int, id func(i int) string) {
+	first := map[string]int{}
+	for i := 0; i < count; i++ {
+		if id(i) == "" {
+			continue
+		}
+		if previous, found := first[id(i)]; found {
+			entry := v.entry(array, i)
+			entry.report(identity, "duplicate %s %q (already defined at line %d)", identity, id(i), v.entry(array, previous).line(identity))
+			continue
+		}
+		first[id(i)] = i
+	}
+}
+
+// configEntryValidator validates an entry of an array of tables
+type configEntryValidator struct {
+	v     *configValidator
+	table string
+	index int
+}
+
+// line returns the line of the key of the entry (or the line of the header of the entry if the key is not set)
+func (e configEntryValidator) line(key string) int {
+	if line := e.v.lines.entryKey(e.table, e.index, key); line != 0 {
+		return line
+	}
+	return e.v.lines.occurrence(e.table, e.index)
+}
+
+func (e configEntryValidator) report(key string, format string, args ...any) {
+	e.v.report(e.line(key), "[[%s]] #%d: %s", e.table, e.index+1, fmt.Sprintf(format, args...))
+}
+
+// required reports the key if none of its values is set
+func (e configEntryValidator) required(key string, values ...string) {
+	for _, value := range values {
+		if value != "" {
+			return
+		}
+	}
+	e.report(key, "missing required field %s", key)
+}
+
+// regex reports the key if its pattern is not a valid regex with the named group
+func (e configEntryValidator) regex(key string, pattern string, group string) {
+	if _, err := compileRegex(pattern, group); err != nil {
+		e.report(key, "invalid %s %q: %s", key, pattern, err)
+	}
+}
+
+// configKeyLines are the lines of the keys and the table headers of a configuration file, in the order of the file
+type configKeyLines struct {
+	// dotted key (for example "fingerprints.agents.agent-name") to the lines of its occurrences
+	keys map[string][]int
+}
+
+// occurrence returns the line of the n-th occurrence of the key (0 if it is not found)
+func (l configKeyLines) occurrence(key string, n int) int {
+	if lines := l.keys[key]; n < len(lines) {
+		return lines[n]
+	}
+	return 0
+}
+
+// entryKey returns the line of the key of the n-th entry of the array of tables (0 if it is not found)
+func (l configKeyLines) entryKey(table string, n int, key string) int {
+	start := l.occurrence(table, n)
+	if start == 0 {
+		return 0
+	}
+	end := l.occurrence(table, n+1)
+	for _, line := range l.keys[table+"."+key] {
+		if line > start && (end == 0 || line < end) {
+			return line
+		}
+	}
+	return 0
+}
+
+// configTablePattern matches the header of a table or of an entry of an array of tables
+var configTablePattern = regexp.MustCompile(`^\s*\[\[?\s*([^\[\]]+?)\s*\]\]?`)
+
+// configKeyPattern matches a key (bare, dotted or quoted) at the start of a line of a TOML file
+var configKeyPattern = regexp.MustCompile(`^\s*([A-Za-z0-9_.\-"' ]+?)\s*=`)
+
+// locateConfigKeys returns the lines of the keys of a TOML file.
+// It only understands the TOML used by the configuration (table headers and keys at the start of the lines)
+// and the keys that it does not locate are reported without a line.
+func locateConfigKeys(content string) configKeyLines {
+	lines := configKeyLines{keys: map[string][]int{}}
+	table := ""
+	// the lines of a multi-line string or array are not keys
+	inValue := false
+	for i, text := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(text)
+		if inValue {
+			inValue = !strings.HasSuffix(trimmed, "]") && !strings.HasSuffix(trimmed, `"""`) && !strings.HasSuffix(trimmed, `'''`)
+			continue
+		}
+		if matches := configTablePattern.FindStringSubmatch(text); matches != nil {
+			table = normalizeConfigKey(matches[1])
+			lines.keys[table] = append(lines.keys[table], i+1)
+			continue
+		}
+		matches := configKeyPattern.FindStringSubmatch(text)
+		if matches == nil {
+			continue
+		}
+		key := normalizeConfigKey(matches[1])
+		if table != "" {
+			key = table + "." + key
+		}
+		lines.keys[key] = append(lines.keys[key], i+1)
+		value := strings.TrimSpace(text[len(matches[0]):])
+		inValue = (strings.HasPrefix(value, "[") && !strings.Contains(value, "]")) ||
+			(strings.HasPrefix(value, `"""`) && strings.Count(value, `"""`) == 1) ||
+			(strings.HasPrefix(value, `'''`) && strings.Count(value, `'''`) == 1)
+	}
+	return lines
+}
+
+// normalizeConfigKey returns the dotted key formatted like toml.Key.String (without the spaces and the quotes of its parts)
+func normalizeConfigKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return toml.Key(parts).String()
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	problems, err := ValidateConfig("../../../extractor/config/config.toml")
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+
+	_, err = ValidateConfig(filepath.Join(t.TempDir(), "config.toml"))
+	assert.Error(t, err)
+}
+
+func TestValidateConfigProblems(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "config.toml"), []byte(`[fingerprints]
+version-detection = "statc"
+
+[[fingerprints.version-executables]]
+process-names = ["node"]
+runtime-kind-name = "Node.js"
+version-regex = '^v(\S+)'
+capture = "stdin"
+
+[[fingerprints.agents]] # a Java agent
+agent-name = "Acme"
+java-premain-class-prefx = "com.acme."
+
+[[fingerprints.file-rules]]
+runtime-name = "Acme Server"
+paths = [
+  "/opt/acme/VERSION",
+]
+extract = "regex"
+regex = '(\d+'
+
+[[fingerprints.file-rules]]
+paths = ["/opt/acme/version.json"]
+extract = "json"
+runtime-name = "Acme Server"
+
+[[fingerprints.java]]
+main-class = "com.acme.Main"
+`), 0644))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, ConfigDropInDir), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ConfigDropInDir, "10-site.toml"), []byte("[fingerprints\n"), 0644))
+
+	problems, err := ValidateConfig(filepath.Join(dir, "config.toml"))
+	assert.NoError(t, err)
+	lines := []string{}
+	for _, problem := range problems {
+		rel, _ := filepath.Rel(dir, problem.File)
+		problem.File = rel
+		lines = append(lines, problem.String())
+	}
+	assert.Equal(t, []string{
+		`config.toml:2: invalid version-detection "statc" (expected "exec" or "static")`,
+		`config.toml:7: [[fingerprints.version-executables]] #1: invalid version-regex "^v(\\S+)": missing the "version" named group`,
+		`config.toml:8: [[fingerprints.version-executables]] #1: invalid capture "stdin" (expected "stdout", "stderr" or "both")`,
+		`config.toml:12: unknown key fingerprints.agents.java-premain-class-prefx`,
+		`config.toml:20: [[fingerprints.file-rules]] #1: invalid regex "(\\d+": error parsing regexp: missing closing ): ` + "`(\\d+`",
+		`config.toml:22: [[fingerprints.file-rules]] #2: missing required field key`,
+		`config.toml:25: [[fingerprints.file-rules]] #2: duplicate runtime-name "Acme Server" (already defined at line 15)`,
+		`config.toml:27: [[fingerprints.java]] #1: missing required field runtime-name`,
+		`config.toml:27: [[fingerprints.java]] #1: missing required field jar-version-manifest-entry`,
+		`config.d/10-site.toml:2: expected '.' or ']' to end table name, but got '\n' instead`,
+	}, lines)
+}
+
+func TestLocateConfigKeys(t *testing.T) {
+	lines := locateConfigKeys(`[fingerprints]
+version-detection = "static"
+
+[[fingerprints.agents]]
+agent-name = "A"
+node-modules = [
+  "a = b",
+]
+
+[[ fingerprints.agents ]]
+"agent-name" = "B"
+`)
+	assert.Equal(t, 2, lines.occurrence("fingerprints.version-detection", 0))
+	assert.Equal(t, 10, lines.occurrence("fingerprints.agents", 1))
+	assert.Equal(t, 5, lines.entryKey("fingerprints.agents", 0, "agent-name"))
+	assert.Equal(t, 11, lines.entryKey("fingerprints.agents", 1, "agent-name"))
+	assert.Equal(t, 0, lines.entryKey("fingerprints.agents", 1, "node-modules"))
+	assert.Equal(t, 0, lines.occurrence("fingerprints.agents.a", 0))
+}
@@ -2,140 +2,138 @@ package utils
 
 import (
 	"archive/zip"
-	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
-)
 
-func WriteEntries(outputDir string, fileName string, entries map[string]string) {
-	file, err := os.Create(filepath.Join(outputDir, fileName))
-	if err != nil {
-		panic(err)
-	}
-	defer file.Close()
-
-	keys := make([]string, 0, len(entries))
-	for k := range entries {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	for _, k := range keys {
-		file.WriteString(k + "=" + entries[k] + "\n")
-	}
-}
+	"keyvalue"
+)
 
 // / Read a key=value file and return its content in a map.
 // /
-// / Key and values are separated by '='.
-func ReadPropertiesFile(filePath string) (map[string]string, bool) {
-	_, err := os.Stat(filePath)
+// / The file is decoded by the keyvalue package (values can be quoted and escaped).
+// / The file path is resolved in the root filesystem (see ResolvePath).
+func ReadPropertiesFile(root string, filePath string) (map[string]string, bool) {
+	_, err := Stat(root, filePath)
 	if os.IsNotExist(err) {
 		// File does not exist
 		return nil, false
 	}
 
-	file, err := os.Open(filePath)
+	file, err := Open(root, filePath)
 	if err != nil {
 		return nil, false
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	properties := make(map[string]string)
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
-			continue
-		}
-
-		// Split the line into key and value
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			key := strings.Trim(strings.TrimSpace(parts[0]), "\"")
-			value := strings.Trim(strings.TrimSpace(parts[1]), "\"")
-			properties[key] = value
-		}
-	}
-
-	// Check for scanner errors
-	if err := scanner.Err(); err != nil {
+	properties, err := keyvalue.Read(LimitReader(filePath, file))
+	if err != nil {
 		return nil, false
 	}
-
 	return properties, true
 }
 
-func GetExecutableVersionOutput(executable string) (string, error) {
-	out, err := exec.Command(executable, "--version").Output()
+// GetExecutableVersionOutput runs the executable of the root filesystem with the arguments of the version-executable
+// that print its version and returns the output of its captured stream(s).
+// The executable is killed at the timeout of the version-executable or when the timeout of the running fingerprint is exceeded.
+func GetExecutableVersionOutput(root string, executable string, versionExecutable VersionExecutable) (string, error) {
+	resolved, err := ResolvePath(root, executable)
+	if err != nil {
+		return "", err
+	}
+	fingerprintCtx, cancel := deadlineContext()
+	defer cancel()
+	ctx := fingerprintCtx
+	if versionExecutable.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(fingerprintCtx, versionExecutable.Timeout)
+		defer cancelTimeout()
+	}
+
+	cmd := exec.CommandContext(ctx, resolved, versionExecutable.VersionArguments()...)
+	var out bytes.Buffer
+	switch versionExecutable.Capture {
+	case "", CaptureStdout:
+		cmd.Stdout = &out
+	case CaptureStderr:
+		cmd.Stderr = &out
+	case CaptureBoth:
+		cmd.Stdout = &out
+		cmd.Stderr = &out
+	default:
+		return "", fmt.Errorf("unsupported capture %q", versionExecutable.Capture)
+	}
+	err = cmd.Run()
+	if fingerprintCtx.Err() == context.DeadlineExceeded {
+		return "", BudgetExceeded(BudgetTimeout, CurrentBudgets().Timeout.String(), executable)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s did not print its version within %s", executable, versionExecutable.Timeout)
+	}
 	if err != nil {
 		return "", err
 	}
-	return string(out), nil
+	return out.String(), nil
 }
 
-// FindExecutableInPath returns the directory in the $PATH env var that contains the given executable
-func FindExecutableInPath(executable string, pathEnvVar string) (string, error) {
+// FindExecutableInPath returns the path of the given executable in the directories of the $PATH env var
+// (the returned path is a path of the root filesystem)
+func FindExecutableInPath(root string, executable string, pathEnvVar string) (string, error) {
 	paths := strings.Split(pathEnvVar, string(os.PathListSeparator))
 	for _, dir := range paths {
 		fullPath := filepath.Join(dir, executable)
-		if _, err := os.Stat(fullPath); err == nil {
+		if _, err := Stat(root, fullPath); err == nil {
 			return fullPath, nil
 		}
 	}
 	return "", fmt.Errorf("executable %s not found in PATH", executable)
 }
 
-func GetJarManifest(jarPath string) (map[string]string, error) {
-	r, err := zip.OpenReader(jarPath)
+// GetJarManifest returns the manifest of a jar of the root filesystem
+func GetJarManifest(root string, jarPath string) (*Manifest, error) {
+	r, err := OpenJar(root, jarPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open JAR file: %w", err)
 	}
 	defer r.Close()
 
+	manifest, err := ReadJarManifest(&r.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w in jar %s", err, jarPath)
+	}
+	return manifest, nil
+}
+
+// ReadJarManifest returns the manifest of an opened jar
+func ReadJarManifest(r *zip.Reader) (*Manifest, error) {
 	for _, file := range r.File {
 		if file.Name == "META-INF/MANIFEST.MF" {
-			manifest, err := file.Open()
+			manifestFile, err := OpenZipEntry(file)
 			if err != nil {
 				return nil, fmt.Errorf("failed to open manifest file: %w", err)
 			}
-			defer manifest.Close()
+			defer manifestFile.Close()
 
-			var buffer bytes.Buffer
-			if _, err := io.Copy(&buffer, manifest); err != nil {
+			content, err := io.ReadAll(manifestFile)
+			if err != nil {
 				return nil, fmt.Errorf("failed to read manifest file: %w", err)
 			}
-
-			manifestContent := buffer.String()
-
-			manifestEntries := make(map[string]string)
-			currentKey := ""
-			for _, entry := range strings.Split(strings.ReplaceAll(manifestContent, "\r\n", "\n"), "\n") {
-				key, value, keyFound := strings.Cut(entry, ": ")
-				if keyFound {
-					manifestEntries[key] = value
-					currentKey = key
-				} else {
-					manifestEntries[currentKey] = manifestEntries[currentKey] + strings.TrimLeft(entry, " ")
-				}
-			}
-			return manifestEntries, nil
+			return ParseManifest(content)
 		}
 	}
 
-	return nil, fmt.Errorf("manifest file not found in jar %s", jarPath)
+	return nil, fmt.Errorf("manifest file not found")
 }
 
-func JarFileContainsClass(jarPath string, className string) bool {
+func JarFileContainsClass(root string, jarPath string, className string) bool {
 	classFile := strings.ReplaceAll(className, ".", "/") + ".class"
 
-	r, err := zip.OpenReader(jarPath)
+	r, err := OpenJar(root, jarPath)
 	if err != nil {
 		return false
 	}
@@ -148,3 +146,45 @@ func JarFileContainsClass(jarPath string, className string) bool {
 	}
 	return false
 }
+
+// ReadJarEntry reads an entry of a jar of the root filesystem (up to the max-file-bytes budget)
+func ReadJarEntry(root string, jarPath string, entryName string) ([]byte, error) {
+	r, err := OpenJar(root, jarPath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	for _, file := range r.File {
+		if file.Name != entryName {
+			continue
+		}
+		entry, err := OpenZipEntry(file)
+		if err != nil {
+			return nil, err
+		}
+		defer entry.Close()
+		return io.ReadAll(entry)
+	}
+	return nil, fmt.Errorf("entry %s not found in %s", entryName, jarPath)
+}
+
+// OpenJar opens a jar of the root filesystem (that has no more entries than the max-zip-entries budget)
+func OpenJar(root string, jarPath string) (*zip.ReadCloser, error) {
+	if err := checkDeadline(); err != nil {
+		return nil, err
+	}
+	resolved, err := ResolvePath(root, jarPath)
+	if err != nil {
+		return nil, err
+	}
+	r, err := zip.OpenReader(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkZipEntries(jarPath, &r.Reader); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
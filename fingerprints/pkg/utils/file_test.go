@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeExecutable(t *testing.T, path string, script string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+}
+
+func TestGetExecutableVersionOutput(t *testing.T) {
+	withBudgets(t, Budgets{Timeout: time.Minute})
+	root := t.TempDir()
+	writeExecutable(t, filepath.Join(root, "usr/bin/runtime"), "echo \"out $@\"\necho \"err $@\" >&2\n")
+
+	output, err := GetExecutableVersionOutput(root, "/usr/bin/runtime", VersionExecutable{})
+	assert.NoError(t, err)
+	assert.Equal(t, "out --version\n", output)
+
+	output, err = GetExecutableVersionOutput(root, "/usr/bin/runtime", VersionExecutable{Arguments: []string{"-v"}, Capture: CaptureStderr})
+	assert.NoError(t, err)
+	assert.Equal(t, "err -v\n", output)
+
+	output, err = GetExecutableVersionOutput(root, "/usr/bin/runtime", VersionExecutable{Arguments: []string{"-version"}, Capture: CaptureBoth})
+	assert.NoError(t, err)
+	assert.Equal(t, "out -version\nerr -version\n", output)
+
+	_, err = GetExecutableVersionOutput(root, "/usr/bin/runtime", VersionExecutable{Capture: "stdin"})
+	assert.EqualError(t, err, "unsupported capture \"stdin\"")
+}
+
+func TestGetExecutableVersionOutputTimeout(t *testing.T) {
+	withBudgets(t, Budgets{Timeout: time.Minute})
+	root := t.TempDir()
+	writeExecutable(t, filepath.Join(root, "usr/bin/runtime"), "exec sleep 10\n")
+
+	// the timeout of the version-executable is not a budget of the fingerprint
+	_, err := GetExecutableVersionOutput(root, "/usr/bin/runtime", VersionExecutable{Timeout: 100 * time.Millisecond})
+	assert.EqualError(t, err, "/usr/bin/runtime did not print its version within 100ms")
+	assert.Empty(t, ExceededBudgets())
+
+	withBudgets(t, Budgets{Timeout: 100 * time.Millisecond})
+	_, err = GetExecutableVersionOutput(root, "/usr/bin/runtime", VersionExecutable{Timeout: time.Minute})
+	assertBudgetExceeded(t, err, BudgetTimeout)
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Maximum length of the name of a manifest attribute
+const maxManifestAttributeNameLength = 70
+
+// Manifest is a JAR manifest (META-INF/MANIFEST.MF) as defined by the JAR File Specification
+// (https://docs.oracle.com/en/java/javase/21/docs/specs/jar/jar.html#jar-manifest).
+type Manifest struct {
+	// Attributes of the main section (the attributes of the jar)
+	Main Attributes
+	// Attributes of the per-entry sections, by the value of their `Name` attribute (the name of the jar entry).
+	// The `Name` attribute itself is not part of the attributes of its section.
+	Sections map[string]Attributes
+}
+
+// Attributes are the attributes of a manifest section.
+// The names of the attributes are case-insensitive: use Get or Lookup to read them.
+type Attributes map[string]string
+
+// Lookup returns the value of the attribute with that name (compared case-insensitively)
+func (attributes Attributes) Lookup(name string) (string, bool) {
+	if value, found := attributes[name]; found {
+		return value, true
+	}
+	for key, value := range attributes {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// Get returns the value of the attribute with that name (or an empty string if it is absent)
+func (attributes Attributes) Get(name string) string {
+	value, _ := attributes.Lookup(name)
+	return value
+}
+
+// set sets the value of the attribute (replacing the attribute whose name only differs by its case)
+func (attributes Attributes) set(name string, value string) {
+	for key := range attributes {
+		if strings.EqualFold(key, name) {
+			delete(attributes, key)
+		}
+	}
+	attributes[name] = value
+}
+
+// ParseManifest parses the content of a JAR manifest.
+//
+// The manifest is a main section followed by per-entry sections, separated by empty lines.
+// Each line ends with CRLF, LF or CR (the last line may have no line terminator).
+// A line starting with a space continues the value of the previous line (values are wrapped at 72 bytes,
+// possibly in the middle of a multi-byte character): the values are validated as UTF-8 once joined.
+// A per-entry section must start with its `Name` attribute.
+func ParseManifest(content []byte) (*Manifest, error) {
+	manifest := &Manifest{
+		Main:     Attributes{},
+		Sections: map[string]Attributes{},
+	}
+
+	// the per-entry section being read (nil until its Name attribute is read)
+	var section Attributes
+	mainSection := true
+	// the attribute whose value is being read (it can be continued on the next lines)
+	name := ""
+	var value []byte
+	lineNumber, nameLineNumber := 0, 0
+
+	endAttribute := func() error {
+		if name == "" {
+			return nil
+		}
+		defer func() { name, value = "", nil }()
+		if !utf8.Valid(value) {
+			return fmt.Errorf("invalid UTF-8 value of manifest attribute %s (line %d)", name, nameLineNumber)
+		}
+		switch {
+		case mainSection:
+			manifest.Main.set(name, string(value))
+		case section == nil:
+			// a per-entry section starts with its name
+			if !strings.EqualFold(name, "Name") {
+				return fmt.Errorf("manifest section does not start with a Name attribute (line %d)", nameLineNumber)
+			}
+			section = Attributes{}
+			if existing, found := manifest.Sections[string(value)]; found {
+				// the attributes of sections with the same name are merged
+				section = existing
+			}
+			manifest.Sections[string(value)] = section
+		default:
+			section.set(name, string(value))
+		}
+		return nil
+	}
+
+	for len(content) > 0 {
+		line, rest := cutManifestLine(content)
+		content = rest
+		lineNumber++
+
+		switch {
+		case len(line) == 0:
+			// an empty line ends the section
+			if err := endAttribute(); err != nil {
+				return nil, err
+			}
+			mainSection, section = false, nil
+		case line[0] == ' ':
+			if name == "" {
+				return nil, fmt.Errorf("manifest continuation line without attribute (line %d)", lineNumber)
+			}
+			value = append(value, line[1:]...)
+		default:
+			if err := endAttribute(); err != nil {
+				return nil, err
+			}
+			separator := bytes.Index(line, []byte(": "))
+			if separator < 0 {
+				return nil, fmt.Errorf("invalid manifest header %q (line %d)", truncate(string(line), 72), lineNumber)
+			}
+			if err := checkAttributeName(line[:separator]); err != nil {
+				return nil, fmt.Errorf("%w (line %d)", err, lineNumber)
+			}
+			name, nameLineNumber = string(line[:separator]), lineNumber
+			value = append([]byte{}, line[separator+2:]...)
+		}
+	}
+	if err := endAttribute(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// cutManifestLine returns the first line of the content (without its line terminator) and the content after it
+func cutManifestLine(content []byte) ([]byte, []byte) {
+	i := bytes.IndexAny(content, "\r\n")
+	if i < 0 {
+		return content, nil
+	}
+	if content[i] == '\r' && i+1 < len(content) && content[i+1] == '\n' {
+		return content[:i], content[i+2:]
+	}
+	return content[:i], content[i+1:]
+}
+
+// checkAttributeName checks that the name of the attribute is alphanumeric (or `-` and `_` after the first character)
+// and is not longer than 70 bytes
+func checkAttributeName(name []byte) error {
+	if len(name) == 0 || len(name) > maxManifestAttributeNameLength {
+		return fmt.Errorf("invalid manifest attribute name %q", truncate(string(name), 72))
+	}
+	for i, c := range name {
+		isAlphaNum := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		if !isAlphaNum && (i == 0 || c != '-' && c != '_') {
+			return fmt.Errorf("invalid manifest attribute name %q", truncate(string(name), 72))
+		}
+	}
+	return nil
+}
+
+func truncate(s string, length int) string {
+	if len(s) > length {
+		return s[:length] + "..."
+	}
+	return s
+}
@@ -0,0 +1,175 @@
+package utils
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	content := "Manifest-Version: 1.0\r\n" +
+		"Main-Class: org.example.Main\r\n" +
+		"Class-Path: lib/a.jar lib/b.jar lib/c.jar lib/d.jar lib/e.jar lib/f.j\r\n" +
+		" ar lib/g.jar\r\n" +
+		"\r\n" +
+		"Name: org/example/\r\n" +
+		"Implementation-Version: 1.2.3\r\n" +
+		"\r\n" +
+		"Name: org/other/\r\n" +
+		"Sealed: true\r\n"
+
+	manifest, err := ParseManifest([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, Attributes{
+		"Manifest-Version": "1.0",
+		"Main-Class":       "org.example.Main",
+		"Class-Path":       "lib/a.jar lib/b.jar lib/c.jar lib/d.jar lib/e.jar lib/f.jar lib/g.jar",
+	}, manifest.Main)
+	assert.Equal(t, map[string]Attributes{
+		"org/example/": {"Implementation-Version": "1.2.3"},
+		"org/other/":   {"Sealed": "true"},
+	}, manifest.Sections)
+	// the attributes of a section do not leak in the main attributes
+	assert.Equal(t, "", manifest.Main.Get("Implementation-Version"))
+}
+
+func TestParseManifestLineTerminators(t *testing.T) {
+	for _, terminator := range []string{"\r\n", "\n", "\r"} {
+		content := strings.Join([]string{"Manifest-Version: 1.0", "Main-Class: org.exa", " mple.Main", "", "Name: a", "Sealed: true", ""}, terminator)
+		manifest, err := ParseManifest([]byte(content))
+		if assert.NoError(t, err, "terminator %q", terminator) {
+			assert.Equal(t, "org.example.Main", manifest.Main.Get("Main-Class"), "terminator %q", terminator)
+			assert.Equal(t, "true", manifest.Sections["a"].Get("Sealed"), "terminator %q", terminator)
+		}
+	}
+
+	// the last line may have no line terminator
+	manifest, err := ParseManifest([]byte("Manifest-Version: 1.0\nMain-Class: Main"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Main", manifest.Main.Get("Main-Class"))
+}
+
+func TestParseManifestUTF8(t *testing.T) {
+	// the value is wrapped in the middle of the 2 bytes of "é"
+	value := "Équipe Développement"
+	split := strings.Index(value, "é") + 1
+	content := "Implementation-Vendor: " + value[:split] + "\n " + value[split:] + "\n"
+	manifest, err := ParseManifest([]byte(content))
+	assert.NoError(t, err)
+	assert.Equal(t, value, manifest.Main.Get("Implementation-Vendor"))
+
+	_, err = ParseManifest([]byte("Manifest-Version: 1.0\nImplementation-Vendor: \xff\xfe\n"))
+	assert.EqualError(t, err, "invalid UTF-8 value of manifest attribute Implementation-Vendor (line 2)")
+}
+
+func TestParseManifestErrors(t *testing.T) {
+	for content, expected := range map[string]string{
+		"Manifest-Version: 1.0\n\nSealed: true\n":    "manifest section does not start with a Name attribute (line 3)",
+		"Manifest-Version: 1.0\nMain-Class\n":        "invalid manifest header \"Main-Class\" (line 2)",
+		" continued\n":                               "manifest continuation line without attribute (line 1)",
+		"Manifest-Version: 1.0\n-Main-Class: Main\n": "invalid manifest attribute name \"-Main-Class\" (line 2)",
+		strings.Repeat("a", 71) + ": 1\n":            "invalid manifest attribute name \"" + strings.Repeat("a", 71) + "\" (line 1)",
+	} {
+		_, err := ParseManifest([]byte(content))
+		assert.EqualError(t, err, expected, "manifest %q", content)
+	}
+}
+
+func TestAttributesCaseInsensitive(t *testing.T) {
+	manifest, err := ParseManifest([]byte("manifest-version: 1.0\nMAIN-CLASS: org.example.Main\n\nname: a\nsealed: true\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "org.example.Main", manifest.Main.Get("Main-Class"))
+	value, found := manifest.Main.Lookup("Manifest-Version")
+	assert.True(t, found)
+	assert.Equal(t, "1.0", value)
+	_, found = manifest.Main.Lookup("Start-Class")
+	assert.False(t, found)
+	assert.Equal(t, "true", manifest.Sections["a"].Get("Sealed"))
+
+	// the last value of an attribute repeated with another case is kept
+	manifest, err = ParseManifest([]byte("Main-Class: First\nmain-class: Second\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, Attributes{"main-class": "Second"}, manifest.Main)
+}
+
+// writeManifest writes the manifest like the jar tool, with lines wrapped at 72 bytes
+func writeManifest(manifest *Manifest) []byte {
+	var buffer bytes.Buffer
+	writeAttribute := func(name string, value string) {
+		line := []byte(name + ": " + value)
+		for len(line) > 72 {
+			buffer.Write(line[:72])
+			buffer.WriteString("\r\n ")
+			line = line[72:]
+		}
+		buffer.Write(line)
+		buffer.WriteString("\r\n")
+	}
+	writeAttributes := func(attributes Attributes) {
+		names := make([]string, 0, len(attributes))
+		for name := range attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			writeAttribute(name, attributes[name])
+		}
+	}
+
+	writeAttributes(manifest.Main)
+	sections := make([]string, 0, len(manifest.Sections))
+	for name := range manifest.Sections {
+		sections = append(sections, name)
+	}
+	sort.Strings(sections)
+	for _, name := range sections {
+		buffer.WriteString("\r\n")
+		writeAttribute("Name", name)
+		writeAttributes(manifest.Sections[name])
+	}
+	return buffer.Bytes()
+}
+
+func FuzzParseManifest(f *testing.F) {
+	f.Add([]byte("Manifest-Version: 1.0\r\nMain-Class: org.example.Main\r\n\r\nName: org/example/\r\nSealed: true\r\n"))
+	f.Add([]byte("Manifest-Version: 1.0\nClass-Path: lib/a.jar lib/b.ja\n r\n"))
+	f.Add([]byte("Implementation-Vendor: \xc3\r \xa9quipe\r"))
+	f.Add([]byte("\n\nName: a\n\nName: a\nSealed: true"))
+	f.Fuzz(func(t *testing.T, content []byte) {
+		manifest, err := ParseManifest(content)
+		if err != nil {
+			return
+		}
+		for _, section := range append([]Attributes{manifest.Main}, mapValues(manifest.Sections)...) {
+			for name, value := range section {
+				if err := checkAttributeName([]byte(name)); err != nil {
+					t.Fatalf("invalid attribute name %q", name)
+				}
+				if !utf8.ValidString(value) || strings.ContainsAny(value, "\r\n") {
+					t.Fatalf("invalid value %q of attribute %s", value, name)
+				}
+			}
+		}
+
+		// the values are the same once the manifest is written again
+		written := writeManifest(manifest)
+		reparsed, err := ParseManifest(written)
+		if err != nil {
+			t.Fatalf("unable to parse the written manifest %q: %v", written, err)
+		}
+		assert.Equal(t, manifest.Main, reparsed.Main)
+		assert.Equal(t, manifest.Sections, reparsed.Sections)
+	})
+}
+
+func mapValues(sections map[string]Attributes) []Attributes {
+	values := make([]Attributes, 0, len(sections))
+	for _, section := range sections {
+		values = append(values, section)
+	}
+	return values
+}
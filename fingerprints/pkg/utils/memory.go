@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ParseJavaMemorySize returns the number of bytes of a JVM memory size option value
+// (for example `512m` or `2G` for `-Xmx`)
+func ParseJavaMemorySize(size string) (int64, error) {
+	if size == "" {
+		return 0, fmt.Errorf("empty memory size")
+	}
+	multiplier := int64(1)
+	switch strings.ToLower(size[len(size)-1:]) {
+	case "k":
+		multiplier = 1024
+	case "m":
+		multiplier = 1024 * 1024
+	case "g":
+		multiplier = 1024 * 1024 * 1024
+	case "t":
+		multiplier = 1024 * 1024 * 1024 * 1024
+	}
+	if multiplier != 1 {
+		size = size[:len(size)-1]
+	}
+	value, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory size %s: %w", size, err)
+	}
+	return value * multiplier, nil
+}
+
+// GetContainerMemoryLimit returns the memory limit (in bytes) of the cgroup of the container
+// (from cgroup v2 `memory.max` or cgroup v1 `memory.limit_in_bytes`).
+// It returns false if the container has no memory limit.
+func GetContainerMemoryLimit(cgroupDir string) (int64, bool) {
+	candidates := []string{
+		filepath.Join(cgroupDir, "memory.max"),
+		filepath.Join(cgroupDir, "memory", "memory.limit_in_bytes"),
+	}
+	for _, candidate := range candidates {
+		content, err := os.ReadFile(candidate)
+		if err != nil {
+			continue
+		}
+		value := strings.TrimSpace(string(content))
+		if value == "max" {
+			return 0, false
+		}
+		limit, err := strconv.ParseInt(value, 10, 64)
+		// cgroup v1 reports a very large value when there is no limit
+		if err != nil || limit >= 1<<62 {
+			return 0, false
+		}
+		return limit, true
+	}
+	return 0, false
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJavaMemorySize(t *testing.T) {
+	for size, expected := range map[string]int64{
+		"1024": 1024,
+		"64k":  64 * 1024,
+		"512m": 512 * 1024 * 1024,
+		"2G":   2 * 1024 * 1024 * 1024,
+	} {
+		actual, err := ParseJavaMemorySize(size)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, size)
+	}
+
+	_, err := ParseJavaMemorySize("")
+	assert.Error(t, err)
+	_, err = ParseJavaMemorySize("2x")
+	assert.Error(t, err)
+}
+
+func TestGetContainerMemoryLimit(t *testing.T) {
+	cgroupDir := t.TempDir()
+	_, exists := GetContainerMemoryLimit(cgroupDir)
+	assert.False(t, exists)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(cgroupDir, "memory.max"), []byte("max\n"), 0644))
+	_, exists = GetContainerMemoryLimit(cgroupDir)
+	assert.False(t, exists)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(cgroupDir, "memory.max"), []byte("536870912\n"), 0644))
+	limit, exists := GetContainerMemoryLimit(cgroupDir)
+	assert.True(t, exists)
+	assert.Equal(t, int64(536870912), limit)
+}
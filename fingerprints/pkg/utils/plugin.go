@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Name of the manifest of a fingerprint plugin, in the directory of the plugin
+const PluginManifestFile = "plugin.toml"
+
+// Runtime components printed by the fingerprint plugins
+const (
+	// the runtimes (and frameworks) of the application (default)
+	PluginOutputRuntimes = "runtimes"
+	// the monitoring agents instrumenting the application
+	PluginOutputAgents = "agents"
+)
+
+// PluginManifest describes an external fingerprint executable of the plugin directory.
+//
+// The executable is run like the fingerprints of the fpr binary (`<executable> [--root <dir>] <output-dir>`)
+// and prints the runtime components that it detects as key=value lines (see the keyvalue module).
+type PluginManifest struct {
+	// Name of the plugin (the name of its directory if it is not set)
+	Name string `toml:"name,omitempty"`
+	// Path of the executable, relative to the directory of the plugin
+	Executable string `toml:"executable"`
+	// Patterns (with the syntax of path.Match) of the names of the processes that the plugin applies to
+	// (all the processes if it is not set)
+	ProcessNames []string `toml:"process-names,omitempty"`
+	// Paths (with the syntax of path.Match) of the files of the container that must all exist for the plugin to apply
+	Files []string `toml:"files,omitempty"`
+	// Directory that the relative paths of the files are resolved against
+	// (FileRuleRelativeToRoot, FileRuleRelativeToCwd or FileRuleRelativeToInstallDir; FileRuleRelativeToRoot if it is not set)
+	RelativeTo string `toml:"relative-to,omitempty"`
+	// Runtime components printed by the executable (PluginOutputRuntimes or PluginOutputAgents; PluginOutputRuntimes if it is not set)
+	Output string `toml:"output,omitempty"`
+	// Confidence of the printed components ("high", "medium" or "low"; "medium" if it is not set)
+	Confidence string `toml:"confidence,omitempty"`
+	// Duration after which the executable is killed (for example "5s"). The timeout budget of the fingerprint still applies.
+	Timeout time.Duration `toml:"timeout,omitempty"`
+
+	// Directory of the plugin
+	Dir string `toml:"-"`
+}
+
+// AppliesTo returns true if the plugin applies to a process with that name
+func (p PluginManifest) AppliesTo(processName string) bool {
+	if len(p.ProcessNames) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(p.ProcessNames, func(pattern string) bool {
+		matched, _ := path.Match(pattern, processName)
+		return matched
+	})
+}
+
+// ExecutablePath returns the path of the executable of the plugin
+func (p PluginManifest) ExecutablePath() string {
+	return filepath.Join(p.Dir, p.Executable)
+}
+
+// validate returns the problems of the manifest
+func (p PluginManifest) validate() []string {
+	problems := []string{}
+	if p.Executable == "" {
+		problems = append(problems, "missing required field executable")
+	} else if filepath.IsAbs(p.Executable) || !filepath.IsLocal(p.Executable) {
+		problems = append(problems, fmt.Sprintf("executable %q is not a path in the directory of the plugin", p.Executable))
+	}
+	for _, pattern := range append(slices.Clone(p.ProcessNames), p.Files...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid pattern %q: %s", pattern, err))
+		}
+	}
+	switch p.RelativeTo {
+	case "", FileRuleRelativeToRoot, FileRuleRelativeToCwd, FileRuleRelativeToInstallDir:
+	default:
+		problems = append(problems, fmt.Sprintf("invalid relative-to %q (expected %q, %q or %q)",
+			p.RelativeTo, FileRuleRelativeToRoot, FileRuleRelativeToCwd, FileRuleRelativeToInstallDir))
+	}
+	switch p.Output {
+	case "", PluginOutputRuntimes, PluginOutputAgents:
+	default:
+		problems = append(problems, fmt.Sprintf("invalid output %q (expected %q or %q)", p.Output, PluginOutputRuntimes, PluginOutputAgents))
+	}
+	switch p.Confidence {
+	case "", "high", "medium", "low":
+	default:
+		problems = append(problems, fmt.Sprintf("invalid confidence %q (expected \"high\", \"medium\" or \"low\")", p.Confidence))
+	}
+	return problems
+}
+
+// ReadPlugins returns the fingerprint plugins of the plugin directory, sorted by name.
+// Each plugin is a sub-directory containing a plugin.toml manifest (see PluginManifest).
+//
+// A plugin whose manifest is invalid is not returned and its problems are reported by the error
+// so that it does not prevent the other plugins from running.
+// There are no plugins if the directory does not exist.
+func ReadPlugins(dir string) ([]PluginManifest, error) {
+	manifests, err := filepath.Glob(filepath.Join(dir, "*", PluginManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(manifests)
+
+	plugins := []PluginManifest{}
+	errs := []error{}
+	names := map[string]string{}
+	for _, manifest := range manifests {
+		plugin, err := readPluginManifest(manifest)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", manifest, err))
+			continue
+		}
+		if previous, found := names[plugin.Name]; found {
+			errs = append(errs, fmt.Errorf("%s: duplicate plugin %q (already defined by %s)", manifest, plugin.Name, previous))
+			continue
+		}
+		names[plugin.Name] = manifest
+		plugins = append(plugins, plugin)
+	}
+	sort.SliceStable(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins, errors.Join(errs...)
+}
+
+// readPluginManifest reads and validates the manifest of a plugin
+func readPluginManifest(manifest string) (PluginManifest, error) {
+	content, err := os.ReadFile(manifest)
+	if err != nil {
+		return PluginManifest{}, err
+	}
+	var plugin PluginManifest
+	md, err := toml.Decode(string(content), &plugin)
+	if err != nil {
+		return PluginManifest{}, err
+	}
+	problems := []string{}
+	for _, key := range md.Undecoded() {
+		problems = append(problems, fmt.Sprintf("unknown key %s", key))
+	}
+	problems = append(problems, plugin.validate()...)
+	if len(problems) > 0 {
+		return PluginManifest{}, errors.New(strings.Join(problems, "; "))
+	}
+
+	plugin.Dir = filepath.Dir(manifest)
+	if plugin.Name == "" {
+		plugin.Name = filepath.Base(plugin.Dir)
+	}
+	return plugin, nil
+}
+
+// RunPlugin runs the executable of the plugin for the process context of the output directory
+// (against the root filesystem if root is set) and returns its standard output.
+// Its standard error is forwarded to the standard error of the fingerprint.
+// The executable is killed at the timeout of the plugin or when the timeout of the running fingerprint is exceeded.
+func RunPlugin(plugin PluginManifest, root string, outputDir string) ([]byte, error) {
+	fingerprintCtx, cancel := deadlineContext()
+	defer cancel()
+	ctx := fingerprintCtx
+	if plugin.Timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(fingerprintCtx, plugin.Timeout)
+		defer cancelTimeout()
+	}
+
+	args := []string{outputDir}
+	if root != "" {
+		args = append([]string{"--root", root}, args...)
+	}
+	cmd := exec.CommandContext(ctx, plugin.ExecutablePath(), args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	// the children of a killed plugin may keep its output open
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	if fingerprintCtx.Err() == context.DeadlineExceeded {
+		return nil, BudgetExceeded(BudgetTimeout, CurrentBudgets().Timeout.String(), plugin.Name)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("plugin %s did not complete within %s", plugin.Name, plugin.Timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s failed: %w", plugin.Name, err)
+	}
+	return out.Bytes(), nil
+}
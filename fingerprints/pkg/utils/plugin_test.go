@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writePluginManifest(t *testing.T, dir string, plugin string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, plugin), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, plugin, PluginManifestFile), []byte(content), 0644))
+}
+
+func TestReadPlugins(t *testing.T) {
+	dir := t.TempDir()
+	writePluginManifest(t, dir, "tracer", "name = \"acme-tracer\"\nexecutable = \"tracer\"\noutput = \"agents\"\ntimeout = \"5s\"\n")
+	writePluginManifest(t, dir, "acme", "executable = \"bin/acme\"\nprocess-names = [\"java\", \"acme-*\"]\nfiles = [\"lib/acme-core-*.jar\"]\nrelative-to = \"cwd\"\n")
+	writePluginManifest(t, dir, "invalid", "executable = \"../acme/bin/acme\"\noutput = \"frameworks\"\nprocess = \"java\"\n")
+	writePluginManifest(t, dir, "duplicate", "name = \"acme\"\nexecutable = \"acme\"\n")
+
+	plugins, err := ReadPlugins(dir)
+	assert.Equal(t, []PluginManifest{
+		{Name: "acme", Executable: "bin/acme", ProcessNames: []string{"java", "acme-*"}, Files: []string{"lib/acme-core-*.jar"}, RelativeTo: FileRuleRelativeToCwd, Dir: filepath.Join(dir, "acme")},
+		{Name: "acme-tracer", Executable: "tracer", Output: PluginOutputAgents, Timeout: 5 * time.Second, Dir: filepath.Join(dir, "tracer")},
+	}, plugins)
+	assert.EqualError(t, err, filepath.Join(dir, "duplicate", PluginManifestFile)+`: duplicate plugin "acme" (already defined by `+filepath.Join(dir, "acme", PluginManifestFile)+")\n"+
+		filepath.Join(dir, "invalid", PluginManifestFile)+`: unknown key process; executable "../acme/bin/acme" is not a path in the directory of the plugin; invalid output "frameworks" (expected "runtimes" or "agents")`)
+
+	assert.True(t, plugins[0].AppliesTo("acme-server"))
+	assert.False(t, plugins[0].AppliesTo("node"))
+	assert.True(t, plugins[1].AppliesTo("node"))
+
+	plugins, err = ReadPlugins(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, plugins)
+}
+
+func TestRunPluginTimeout(t *testing.T) {
+	withBudgets(t, Budgets{Timeout: time.Minute})
+	dir := t.TempDir()
+	writeExecutable(t, filepath.Join(dir, "slow", "run"), "exec sleep 10\n")
+
+	start := time.Now()
+	_, err := RunPlugin(PluginManifest{Name: "slow", Executable: "run", Timeout: 100 * time.Millisecond, Dir: filepath.Join(dir, "slow")}, "", t.TempDir())
+	assert.EqualError(t, err, "plugin slow did not complete within 100ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Name of the file of the process context in the output directory of the fingerprints (alongside config.toml)
+const ProcessContextFile = "process.json"
+
+// Version of the format of the process context
+const ProcessContextVersion = 1
+
+// Length of the name of a Linux process (TASK_COMM_LEN without the terminating null byte)
+const processNameLength = 15
+
+// ProcessContext describes the container process that the fingerprints inspect.
+//
+// It is written by the extractor (or by the image-scanner of the exporter for the process of an image)
+// to the process.json file of the output directory and read by the fingerprints with Context.Process.
+type ProcessContext struct {
+	Version int `json:"version"`
+	// PID of the process (0 if the process is not running)
+	PID int `json:"pid,omitempty"`
+	// UID of the user running the process (nil if it is unknown)
+	UID *int `json:"uid,omitempty"`
+	// Name of the process (the first 15 characters of the name of its executable)
+	Name string `json:"name"`
+	// Current working directory of the process
+	Cwd string `json:"cwd"`
+	// The executable of the process and its arguments
+	CommandLine []string `json:"command-line"`
+	// Environment variables of the process
+	Environ map[string]string `json:"environ"`
+}
+
+// Executable returns the executable of the process (the first argument of its command line)
+func (process ProcessContext) Executable() string {
+	if len(process.CommandLine) == 0 {
+		return ""
+	}
+	return process.CommandLine[0]
+}
+
+// Env returns the value of the environment variable of the process (or an empty string if it is not set)
+func (process ProcessContext) Env(name string) string {
+	return process.Environ[name]
+}
+
+// GetProcessContext reads the process context from the file at path.
+// The name of the process is derived from its executable when it is not set.
+func GetProcessContext(path string) (ProcessContext, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ProcessContext{}, err
+	}
+
+	var process ProcessContext
+	if err := json.Unmarshal(content, &process); err != nil {
+		return ProcessContext{}, err
+	}
+	if process.Version != ProcessContextVersion {
+		return ProcessContext{}, fmt.Errorf("unsupported process context version %d", process.Version)
+	}
+	if len(process.CommandLine) == 0 {
+		return ProcessContext{}, fmt.Errorf("the command line of the process is empty")
+	}
+	if process.Name == "" {
+		process.Name = filepath.Base(process.CommandLine[0])
+		if len(process.Name) > processNameLength {
+			process.Name = process.Name[:processNameLength]
+		}
+	}
+	if process.Environ == nil {
+		process.Environ = map[string]string{}
+	}
+	return process, nil
+}
+
+// WriteProcessContext writes the process context to the process.json file of the directory
+func WriteProcessContext(dir string, process ProcessContext) error {
+	process.Version = ProcessContextVersion
+	content, err := json.MarshalIndent(process, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ProcessContextFile), content, 0644)
+}
@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"rootfs"
+)
+
+// The helpers of this file access the paths of a root filesystem.
+//
+// When root is empty, the paths are accessed as is: the fingerprints run in the filesystem of the container
+// (after joining its mount namespace).
+// Otherwise, root is a directory containing the root filesystem of the container (an extracted image layer,
+// a mounted snapshot or a test fixture directory) and the paths are resolved as if root was the root directory
+// of the process: absolute symbolic links are resolved relative to root and `..` never goes above root,
+// so that a path never escapes root (see the rootfs module).
+
+// ResolvePath returns the path to use to access the given path of the root filesystem
+func ResolvePath(root string, path string) (string, error) {
+	if root == "" {
+		return path, nil
+	}
+	resolved, err := rootfs.Resolve(root, path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, resolved), nil
+}
+
+// EvalSymlinks returns the path of the root filesystem after the evaluation of its symbolic links
+func EvalSymlinks(root string, path string) (string, error) {
+	if root == "" {
+		return filepath.EvalSymlinks(path)
+	}
+	resolved, err := rootfs.Resolve(root, path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Lstat(filepath.Join(root, resolved)); err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+// Open opens a file of the root filesystem for reading
+func Open(root string, path string) (*os.File, error) {
+	if err := checkDeadline(); err != nil {
+		return nil, err
+	}
+	resolved, err := ResolvePath(root, path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(resolved)
+}
+
+// ReadFile reads a file of the root filesystem (up to the max-file-bytes budget)
+func ReadFile(root string, path string) ([]byte, error) {
+	file, err := Open(root, path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(LimitReader(path, file))
+}
+
+// Stat returns the info of a file of the root filesystem
+func Stat(root string, path string) (os.FileInfo, error) {
+	resolved, err := ResolvePath(root, path)
+	if err != nil {
+		return nil, err
+	}
+	return os.Stat(resolved)
+}
+
+// ReadDir reads a directory of the root filesystem
+func ReadDir(root string, path string) ([]os.DirEntry, error) {
+	resolved, err := ResolvePath(root, path)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadDir(resolved)
+}
+
+// Glob returns the paths of the root filesystem matching the pattern (with the syntax of filepath.Match).
+// The returned paths are paths of the root filesystem, not prefixed by root.
+func Glob(root string, pattern string) []string {
+	if root == "" {
+		matches, _ := filepath.Glob(pattern)
+		return matches
+	}
+	matches := []string{"/"}
+	for _, component := range strings.Split(filepath.Clean("/"+pattern), "/") {
+		if component == "" {
+			continue
+		}
+		if !hasMeta(component) {
+			for i := range matches {
+				matches[i] = filepath.Join(matches[i], component)
+			}
+			continue
+		}
+		var next []string
+		for _, dir := range matches {
+			entries, err := ReadDir(root, dir)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				if matched, _ := filepath.Match(component, entry.Name()); matched {
+					next = append(next, filepath.Join(dir, entry.Name()))
+				}
+			}
+		}
+		matches = next
+	}
+
+	existing := []string{}
+	for _, match := range matches {
+		if resolved, err := ResolvePath(root, match); err == nil {
+			if _, err := os.Lstat(resolved); err == nil {
+				existing = append(existing, match)
+			}
+		}
+	}
+	return existing
+}
+
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolvePath(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "usr", "lib", "jvm", "java-17", "bin"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "usr", "lib", "jvm", "java-17", "bin", "java"), nil, 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "usr", "bin"), 0755))
+	// absolute and relative symbolic links
+	assert.NoError(t, os.Symlink("/usr/lib/jvm/java-17/bin/java", filepath.Join(root, "usr", "bin", "java")))
+	assert.NoError(t, os.Symlink("../lib/jvm/java-17", filepath.Join(root, "usr", "bin", "jdk")))
+	// symbolic links that would escape the root
+	assert.NoError(t, os.Symlink("/etc/passwd", filepath.Join(root, "passwd")))
+	assert.NoError(t, os.Symlink("../../../../../../etc", filepath.Join(root, "usr", "bin", "etc")))
+	// symbolic link loop
+	assert.NoError(t, os.Symlink("loop", filepath.Join(root, "loop")))
+
+	path, err := ResolvePath(root, "/usr/bin/java")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "usr", "lib", "jvm", "java-17", "bin", "java"), path)
+
+	path, err = ResolvePath(root, "/usr/bin/jdk/bin/java")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "usr", "lib", "jvm", "java-17", "bin", "java"), path)
+
+	path, err = ResolvePath(root, "/passwd")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "etc", "passwd"), path)
+
+	path, err = ResolvePath(root, "/usr/bin/etc/passwd")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "etc", "passwd"), path)
+
+	path, err = ResolvePath(root, "/../../usr/../file")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "file"), path)
+
+	path, err = ResolvePath(root, "/usr/../../usr/bin/jdk/../../../bin/etc/passwd")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "etc", "passwd"), path)
+
+	// `..` does not lead back from a missing component
+	_, err = ResolvePath(root, "/missing/../passwd")
+	assert.True(t, os.IsNotExist(err), "unexpected error %v", err)
+	_, err = ResolvePath(root, "/missing/other/../../usr/bin/java")
+	assert.True(t, os.IsNotExist(err), "unexpected error %v", err)
+
+	_, err = ResolvePath(root, "/loop")
+	assert.ErrorContains(t, err, "too many levels of symbolic links")
+
+	// without root, the path is not changed
+	path, err = ResolvePath("", "/usr/bin/java")
+	assert.NoError(t, err)
+	assert.Equal(t, "/usr/bin/java", path)
+
+	resolved, err := EvalSymlinks(root, "/usr/bin/java")
+	assert.NoError(t, err)
+	assert.Equal(t, "/usr/lib/jvm/java-17/bin/java", resolved)
+}
+
+func TestReadFileDoesNotEscapeRoot(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(outside, "hostname"), []byte("host"), 0644))
+	// an absolute symbolic link to a file outside of the root, reached after a missing component
+	assert.NoError(t, os.Symlink(filepath.Join(outside, "hostname"), filepath.Join(root, "evil")))
+
+	_, err := ReadFile(root, "/missing/../evil")
+	assert.True(t, os.IsNotExist(err), "unexpected error %v", err)
+	_, err = ReadFile(root, "/evil")
+	assert.True(t, os.IsNotExist(err), "unexpected error %v", err)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, outside), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, outside, "hostname"), []byte("container"), 0644))
+	content, err := ReadFile(root, "/evil")
+	assert.NoError(t, err)
+	assert.Equal(t, "container", string(content))
+}
+
+func TestGlob(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"usr/lib/x86_64-linux-gnu", "usr/lib/aarch64-linux-gnu", "lib64"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "usr", "lib", "x86_64-linux-gnu", "libssl.so.3"), nil, 0644))
+	assert.NoError(t, os.Symlink("/usr/lib", filepath.Join(root, "lib")))
+
+	assert.Equal(t, []string{"/usr/lib/x86_64-linux-gnu/libssl.so.3"}, Glob(root, "/usr/lib/*-linux-gnu/libssl.so.*"))
+	// the returned paths are the paths of the root filesystem (not resolved)
+	assert.Equal(t, []string{"/lib/x86_64-linux-gnu/libssl.so.3"}, Glob(root, "/lib/*/libssl.so.*"))
+	assert.Empty(t, Glob(root, "/lib64/libssl.so.*"))
+}
+
+func TestReadPropertiesFileWithRoot(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "usr", "lib"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "usr", "lib", "os-release"), []byte("ID=\"rhel\"\nVERSION_ID=\"9.4\"\n"), 0644))
+	assert.NoError(t, os.Symlink("../usr/lib/os-release", filepath.Join(root, "etc", "os-release")))
+
+	properties, exists := ReadPropertiesFile(root, "/etc/os-release")
+	assert.True(t, exists)
+	assert.Equal(t, map[string]string{"ID": "rhel", "VERSION_ID": "9.4"}, properties)
+
+	_, exists = ReadPropertiesFile(root, "/etc/redhat-release")
+	assert.False(t, exists)
+}
@@ -0,0 +1,218 @@
+// Package keyvalue reads and writes the key=value files shared by the extractor, the fingerprints and the exporter
+// (for example the container-info.txt file of a container).
+//
+// A file has one `key=value` entry per line. Empty lines and the lines starting with `#` are ignored.
+// The spaces around the key and the value are trimmed and a value enclosed in double or single quotes is unquoted
+// so that the configuration files of the filesystem (/etc/os-release, the release file of a JDK...) can be read too.
+//
+// Keys and values are escaped with a backslash:
+//
+//   - `\\`, `\n`, `\r`, `\t`, `\=`, `\#`, `\"` and `\'` are a backslash, a line feed, a carriage return, a tab,
+//     `=`, `#`, a double quote and a single quote
+//   - `\xHH` is the byte of hexadecimal value HH. It escapes the bytes that are not valid UTF-8, the other control
+//     characters and the spaces at the start or the end of a key or a value
+//
+// Any other backslash is kept as is when the file is read.
+package keyvalue
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Maximum length of a line (the entries are usually short, but a version output may be long)
+const maxLineLength = 1 << 20
+
+// Spaces trimmed around the keys and the values
+const spaces = " \t"
+
+// Escape escapes a key or a value so that it can be written in a key=value file
+func Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			// not valid UTF-8
+			fmt.Fprintf(&b, `\x%02X`, s[i])
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '=', r == '#', r == '"', r == '\'':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7F:
+			fmt.Fprintf(&b, `\x%02X`, r)
+		case r == ' ' && (i == 0 || i == len(s)-1):
+			// the spaces around the keys and the values are trimmed when the file is read
+			b.WriteString(`\x20`)
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// Unescape returns the key or the value escaped by Escape
+func Unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b = append(b, s[i])
+			continue
+		}
+		switch c := s[i+1]; c {
+		case '\\', '=', '#', '"', '\'':
+			b = append(b, c)
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'x':
+			if i+3 < len(s) && isHex(s[i+2]) && isHex(s[i+3]) {
+				b = append(b, unhex(s[i+2])<<4|unhex(s[i+3]))
+				i += 2
+			} else {
+				b = append(b, '\\', c)
+			}
+		default:
+			// not an escape sequence
+			b = append(b, '\\', c)
+		}
+		i++
+	}
+	return string(b)
+}
+
+// Read reads the entries of a key=value file
+func Read(r io.Reader) (map[string]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineLength)
+	entries := make(map[string]string)
+
+	for scanner.Scan() {
+		line := strings.Trim(strings.TrimSuffix(scanner.Text(), "\r"), spaces)
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+
+		key, value, found := cutUnescaped(line, '=')
+		if !found {
+			continue
+		}
+		entries[Unescape(strings.Trim(key, spaces))] = Unescape(unquote(strings.Trim(value, spaces)))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Write writes the entries in a key=value file (sorted by key)
+func Write(w io.Writer, entries map[string]string) error {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bw := bufio.NewWriter(w)
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(bw, "%s=%s\n", Escape(key), Escape(entries[key])); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadFile reads the entries of the key=value file at path.
+// It returns false if the file does not exist or can not be read.
+func ReadFile(path string) (map[string]string, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	entries, err := Read(file)
+	if err != nil {
+		return nil, false
+	}
+	return entries, true
+}
+
+// WriteFile writes the entries in the key=value file at path
+func WriteFile(path string, entries map[string]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Write(file, entries); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// cutUnescaped slices s around the first separator that is not escaped
+func cutUnescaped(s string, separator byte) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case separator:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// unquote removes the double or single quotes that enclose the value (if the closing quote is not escaped)
+func unquote(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	quote := value[0]
+	if (quote != '"' && quote != '\'') || value[len(value)-1] != quote {
+		return value
+	}
+	backslashes := 0
+	for i := len(value) - 2; i > 0 && value[i] == '\\'; i-- {
+		backslashes++
+	}
+	if backslashes%2 == 1 {
+		return value
+	}
+	return value[1 : len(value)-1]
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	default:
+		return c - '0'
+	}
+}
@@ -0,0 +1,134 @@
+package keyvalue
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscape(t *testing.T) {
+	for value, expected := range map[string]string{
+		"8.9":                      "8.9",
+		"v20.11.1\n":               `v20.11.1\n`,
+		"Python 2.7.18\r\n":        `Python 2.7.18\r\n`,
+		"key=value # comment":      `key\=value \# comment`,
+		`"quoted" 'value'`:         `\"quoted\" \'value\'`,
+		`C:\path`:                  `C:\\path`,
+		" padded ":                 `\x20padded\x20`,
+		"tab\tbell\a":              `tab\tbell\x07`,
+		"latin1 caf\xe9":           `latin1 caf\xE9`,
+		"Équipe Développement 日本語": "Équipe Développement 日本語",
+	} {
+		assert.Equal(t, expected, Escape(value), "escape %q", value)
+		assert.Equal(t, value, Unescape(expected), "unescape %q", expected)
+	}
+}
+
+func TestRead(t *testing.T) {
+	content := "# comment\n" +
+		"\n" +
+		"os-release-id=rhel\n" +
+		"  os-release-version-id = 8.9  \r\n" +
+		"ID=\"rhel\"\n" +
+		"NAME='Red Hat'\n" +
+		"PRETTY_NAME=\"Red Hat \\\"Enterprise\\\" Linux\"\n" +
+		"JAVA_VERSION=\"17.0.11\n" +
+		"path=C:\\Program Files\\Java\n" +
+		"escaped\\=key=a\\=b\n" +
+		"not an entry\n"
+
+	entries, err := Read(strings.NewReader(content))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"os-release-id":         "rhel",
+		"os-release-version-id": "8.9",
+		"ID":                    "rhel",
+		"NAME":                  "Red Hat",
+		"PRETTY_NAME":           `Red Hat "Enterprise" Linux`,
+		// an unbalanced quote is kept
+		"JAVA_VERSION": `"17.0.11`,
+		// the backslashes that are not escape sequences are kept
+		"path":        `C:\Program Files\Java`,
+		"escaped=key": "a=b",
+	}, entries)
+}
+
+func TestWriteRead(t *testing.T) {
+	entries := map[string]string{
+		"pod-name":      "my-pod",
+		"pod-namespace": "default",
+		"container-id":  "cri-o://1234",
+		"node":          "v20.11.1\n",
+		"python":        "Python 2.7.18\nGCC 8.5.0\n",
+		"java":          "openjdk version \"17.0.11\" 2024-04-16\r\nOpenJDK Runtime Environment\r\n",
+		"#not-comment":  "=value=",
+		" ":             " ",
+		"binary":        "\x00\xff\xfe\x7f",
+		"empty":         "",
+		"quoted":        `"value"`,
+		`trailing\`:     `\`,
+	}
+
+	var buffer bytes.Buffer
+	assert.NoError(t, Write(&buffer, entries))
+	// one line per entry
+	assert.Equal(t, len(entries), strings.Count(buffer.String(), "\n"))
+	assert.True(t, strings.HasPrefix(buffer.String(), "\\x20=\\x20\n\\#not-comment=\\=value\\=\n"), buffer.String())
+
+	read, err := Read(&buffer)
+	assert.NoError(t, err)
+	assert.Equal(t, entries, read)
+}
+
+func TestWriteReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "container-info.txt")
+	entries := map[string]string{"pod-name": "my-pod", "pod-namespace": "default"}
+	assert.NoError(t, WriteFile(path, entries))
+
+	read, exists := ReadFile(path)
+	assert.True(t, exists)
+	assert.Equal(t, entries, read)
+
+	read, exists = ReadFile(filepath.Join(t.TempDir(), "that-file-does-not-exist"))
+	assert.False(t, exists)
+	assert.Nil(t, read)
+}
+
+func TestReadLongLine(t *testing.T) {
+	value := strings.Repeat("a", 100000)
+	entries, err := Read(strings.NewReader("long=" + value + "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, value, entries["long"])
+
+	_, err = Read(strings.NewReader("too-long=" + strings.Repeat("a", maxLineLength) + "\n"))
+	assert.Error(t, err)
+	_, exists := ReadFile(writeTempFile(t, "too-long="+strings.Repeat("a", maxLineLength)))
+	assert.False(t, exists)
+}
+
+func writeTempFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "file")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func FuzzWriteRead(f *testing.F) {
+	f.Add("pod-name", "my-pod")
+	f.Add("node", "v20.11.1\n")
+	f.Add(" #key= ", "\"value\"\r\n\xff")
+	f.Add(`\x41`, `'\`)
+	f.Fuzz(func(t *testing.T, key string, value string) {
+		assert.Equal(t, value, Unescape(Escape(value)))
+
+		var buffer bytes.Buffer
+		assert.NoError(t, Write(&buffer, map[string]string{key: value}))
+		assert.Equal(t, 1, strings.Count(buffer.String(), "\n"), "written %q", buffer.String())
+		read, err := Read(&buffer)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{key: value}, read)
+	})
+}
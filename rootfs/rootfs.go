@@ -0,0 +1,84 @@
+// Package rootfs resolves the paths of a root filesystem unpacked in a directory of the host.
+//
+// It is shared by the fingerprints (that read an image root filesystem with the --root option)
+// and the image scanner of the exporter (that unpacks the layers of an image) so that they resolve the paths
+// of an untrusted root filesystem the same way.
+package rootfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// maximum number of symbolic links followed when resolving a path (same as the Linux MAXSYMLINKS)
+const maxSymlinks = 40
+
+// Resolve resolves the symbolic links of the path as if root was the root directory and returns the resolved path
+// (an absolute path of the root filesystem, to join to root to access it):
+// absolute symbolic links are resolved relative to root and `..` never goes above root, so that a path never escapes root.
+//
+// The components of the path that do not exist are kept as is (so that the path of a file to create can be resolved),
+// but like the kernel, `.` or `..` after a missing component fails with an ENOENT error
+// (and after a file that is not a directory with an ENOTDIR error):
+// `missing/../etc` does not lead back to an existing directory or symbolic link.
+func Resolve(root string, path string) (string, error) {
+	resolved := "/"
+	remaining := path
+	links := 0
+	// the last resolved component does not exist or is not a directory
+	missing, notDir := false, false
+	for {
+		remaining = strings.TrimLeft(remaining, "/")
+		if remaining == "" {
+			return resolved, nil
+		}
+		var component string
+		component, remaining, _ = strings.Cut(remaining, "/")
+		switch component {
+		case ".", "..":
+			if missing {
+				return "", &os.PathError{Op: "resolve", Path: path, Err: syscall.ENOENT}
+			}
+			if notDir {
+				return "", &os.PathError{Op: "resolve", Path: path, Err: syscall.ENOTDIR}
+			}
+			if component == ".." {
+				// the parent of the root directory is the root directory
+				resolved = filepath.Dir(resolved)
+			}
+			continue
+		}
+
+		next := filepath.Join(resolved, component)
+		info, err := os.Lstat(filepath.Join(root, next))
+		if os.IsNotExist(err) {
+			resolved = next
+			missing = true
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			notDir = !info.IsDir()
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("too many levels of symbolic links in %s", path)
+		}
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+		remaining = target + "/" + remaining
+	}
+}
@@ -0,0 +1,56 @@
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "usr", "lib", "jvm", "java-17", "bin"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "usr", "bin"), 0755))
+	assert.NoError(t, os.Symlink("/usr/lib/jvm/java-17/bin/java", filepath.Join(root, "usr", "bin", "java")))
+	assert.NoError(t, os.Symlink("../lib/jvm/java-17", filepath.Join(root, "usr", "bin", "jdk")))
+	// symbolic links that would escape the root
+	assert.NoError(t, os.Symlink("/tmp/escape-target", filepath.Join(root, "link2")))
+	assert.NoError(t, os.Symlink("/nonexist/../link2", filepath.Join(root, "a")))
+	assert.NoError(t, os.Symlink("../../../../../../etc", filepath.Join(root, "usr", "bin", "etc")))
+	assert.NoError(t, os.Symlink("loop", filepath.Join(root, "loop")))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "usr", "bin", "ls"), nil, 0755))
+
+	for path, expected := range map[string]string{
+		"/usr/bin/java":          "/usr/lib/jvm/java-17/bin/java",
+		"/usr/bin/jdk/bin/java":  "/usr/lib/jvm/java-17/bin/java",
+		"/usr/bin/etc/passwd":    "/etc/passwd",
+		"/../../usr/../file":     "/file",
+		"/link2/file":            "/tmp/escape-target/file",
+		"/missing/other":         "/missing/other",
+		"usr/./bin/../bin/jdk":   "/usr/lib/jvm/java-17",
+		"/usr/bin/jdk/../../jvm": "/usr/lib/jvm",
+	} {
+		resolved, err := Resolve(root, path)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, resolved, path)
+	}
+
+	// like the kernel, `.` and `..` fail after a missing component or a file
+	for path, expected := range map[string]error{
+		"/missing/../usr/bin/java":          syscall.ENOENT,
+		"/missing/../link2/file":            syscall.ENOENT,
+		"/missing/other/../../usr/bin/java": syscall.ENOENT,
+		"/missing/.":                        syscall.ENOENT,
+		"/a/pwned":                          syscall.ENOENT,
+		"/usr/bin/ls/../java":               syscall.ENOTDIR,
+	} {
+		_, err := Resolve(root, path)
+		assert.ErrorIs(t, err, expected, path)
+	}
+
+	_, err := Resolve(root, "/loop")
+	assert.ErrorContains(t, err, "too many levels of symbolic links")
+}